@@ -0,0 +1,204 @@
+package flashtext
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// MatchKind selects how the keys are looked up in the text by `Search` and `Replace`.
+// For all the match kinds `Search` and `Replace` agree: `Replace` substitutes the
+// `cleanWord` of the keys reported by `Search` in the same order, skipping the keys
+// without a `cleanWord` and the ones overlapping an already replaced key (only possible
+// with `Greedy` and `AllOverlapping`, the other kinds never report overlapping keys).
+type MatchKind int
+
+const (
+	// Greedy is the original FlashText walk: the trie is walked from the root and
+	// goes back to the root when the text can't continue the current key, so a key
+	// starting inside a partially consumed key is not found (default). A key which is
+	// a prefix of a longer key found in the text is reported with `IsPrefix=true`
+	// followed by the longer key.
+	Greedy MatchKind = iota
+	// AllOverlapping uses the Aho-Corasick automaton built on top of the trie
+	// (failure and output links) to report every occurrence of every key in a
+	// single pass, including the overlapping ones. The results are ordered by
+	// their end in the text and, for the same end, from the longest to the shortest key.
+	AllOverlapping
+	// LeftmostLongest reports non overlapping keys: scanning the text from left to right
+	// the key starting first wins, and among the keys starting at the same position the
+	// longest one. This is the behaviour of the original python library.
+	LeftmostLongest
+	// LeftmostFirst reports non overlapping keys: the key starting first wins, and among
+	// the keys starting at the same position the one added first to the trie, like the
+	// alternation `key1|key2` of a regex.
+	LeftmostFirst
+	// Shortest reports non overlapping keys: the key ending first wins, and among the
+	// keys ending at the same position the shortest one. The scan resumes right after it.
+	Shortest
+)
+
+// Select the `MatchKind` used by `Search` and `Replace`. The kinds other than `Greedy` use
+// the links of an automaton, built by the first search after an update of the trie
+func (tree *FlashKeywords) SetMatchKind(kind MatchKind) {
+	tree.matchKind = kind
+}
+
+// Returns the `MatchKind` used by `Search` and `Replace`
+func (tree *FlashKeywords) MatchKind() MatchKind {
+	return tree.matchKind
+}
+
+// buildLinks computes the failure and output links of the Aho-Corasick automaton
+// with a breadth-first traversal of the states of the trie, the positions inside the
+// labels included (see `trieState`). The failure link of a state points to the state of
+// its longest proper suffix in the trie, the output link to the node of its longest
+// proper suffix which is a key.
+func (tree *FlashKeywords) buildLinks() {
+	queue := make([]trieState, 0, tree.nbrNodes)
+	root := trieState{node: tree.root}
+	tree.root.links = []stateLinks{{}}
+	tree.maxDepth = 0
+	for _, child := range tree.root.children {
+		child.links = make([]stateLinks, len(child.label)+1)
+		child.links[0] = stateLinks{failure: root}
+		queue = append(queue, trieState{node: child})
+	}
+
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		node := state.node
+		if state.pos < len(node.label) {
+			queue = append(queue, tree.linkState(state, trieState{node, state.pos + 1}, node.label[state.pos]))
+			continue
+		}
+		if node.isWord && node.depth > tree.maxDepth {
+			tree.maxDepth = node.depth
+		}
+		for char, child := range node.children {
+			child.links = make([]stateLinks, len(child.label)+1)
+			queue = append(queue, tree.linkState(state, trieState{node: child}, char))
+		}
+	}
+	atomic.StoreUint32(&tree.linked, 1)
+}
+
+// linksMu serializes the builds of the links by concurrent searches, see `ensureLinks`
+var linksMu sync.Mutex
+
+// ensureLinks builds the links of the automaton if an update left them out of date.
+// The concurrent searches of a trie call it safely: the first one builds the links and
+// the others wait for them, the searches then only read the trie
+func (tree *FlashKeywords) ensureLinks() {
+	if tree.isLinked() {
+		return
+	}
+	linksMu.Lock()
+	defer linksMu.Unlock()
+	if tree.linked == 0 {
+		tree.buildLinks()
+	}
+}
+
+// isLinked reports whether the links of the automaton are up to date
+func (tree *FlashKeywords) isLinked() bool {
+	return atomic.LoadUint32(&tree.linked) == 1
+}
+
+// linkState computes the links of the state `next` reached from the state `prev` by
+// the rune `char`, the links of `prev` being already computed. Returns `next`
+func (tree *FlashKeywords) linkState(prev trieState, next trieState, char rune) trieState {
+	failure := prev.links().failure
+	for {
+		if state, ok := failure.next(char); ok {
+			failure = state
+			break
+		}
+		if failure.node == tree.root {
+			break
+		}
+		failure = failure.links().failure
+	}
+
+	links := next.links()
+	links.failure = failure
+	// the root is a word after `Add("")` but the empty key is never reported
+	if failure.isWord() && failure.node != tree.root {
+		links.output = failure.node
+	} else {
+		links.output = failure.links().output
+	}
+	return next
+}
+
+// resolver selects the non overlapping matches among all the occurrences found by
+// the automaton. The occurrences are received in the order of their end and kept
+// pending until no other occurrence can start before them, the selected matches
+// are given to `fn`.
+type resolver struct {
+	kind    MatchKind
+	cursor  int // end of the last reported match
+	pending []match
+}
+
+func (r *resolver) add(m match) {
+	if m.start < r.cursor {
+		return
+	}
+	if r.kind == Shortest && len(r.pending) > 0 {
+		// same end and received from the longest to the shortest
+		r.pending[0] = m
+		return
+	}
+	r.pending = append(r.pending, m)
+}
+
+// advance is called after each rune, the next occurrences can't start before `limit`
+func (r *resolver) advance(limit int, fn func(m match)) {
+	if len(r.pending) == 0 {
+		return
+	}
+	if r.kind == Shortest {
+		r.report(r.pending[0], fn)
+		return
+	}
+	r.resolve(limit, fn)
+}
+
+func (r *resolver) flush(fn func(m match)) {
+	if len(r.pending) > 0 {
+		r.resolve(math.MaxInt, fn)
+	}
+}
+
+// resolve reports the leftmost matches starting before `limit`
+func (r *resolver) resolve(limit int, fn func(m match)) {
+	for len(r.pending) > 0 {
+		best := r.pending[0]
+		for _, m := range r.pending[1:] {
+			if m.start < best.start ||
+				m.start == best.start && r.kind == LeftmostLongest && m.end > best.end ||
+				m.start == best.start && r.kind == LeftmostFirst && m.order < best.order {
+				best = m
+			}
+		}
+		if best.start >= limit {
+			return
+		}
+		r.report(best, fn)
+	}
+}
+
+// report calls `fn` with `m` and drops the pending matches overlapping it
+func (r *resolver) report(m match, fn func(m match)) {
+	fn(m)
+	r.cursor = m.end
+	pending := r.pending[:0]
+	for _, p := range r.pending {
+		if p.start >= r.cursor {
+			pending = append(pending, p)
+		}
+	}
+	r.pending = pending
+}
@@ -0,0 +1,358 @@
+package flashtext
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// bruteForceSearch returns the spans [start, end) of all the occurrences of the keys,
+// ordered like the `AllOverlapping` results
+func bruteForceSearch(keys []string, text string) [][2]int {
+	var spans [][2]int
+	for _, key := range keys {
+		for i := 0; i+len(key) <= len(text); i++ {
+			if text[i:i+len(key)] == key {
+				spans = append(spans, [2]int{i, i + len(key)})
+			}
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		if spans[i][1] != spans[j][1] {
+			return spans[i][1] < spans[j][1]
+		}
+		return spans[i][0] < spans[j][0]
+	})
+	return spans
+}
+
+func TestAllOverlappingKeyInsidePartialKey(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.Add("abcd")
+	trie.Add("bc")
+	text := "abcx"
+	// the greedy walk loses `bc` after consuming `abc`
+	assert.Equal(t, len(trie.Search(text)), 0)
+
+	trie.SetMatchKind(AllOverlapping)
+	assert.Equal(t, trie.MatchKind(), AllOverlapping)
+	res := trie.Search(text)
+	assert.Equal(t, len(res), 1)
+	assert.Equal(t, res[0].Key, "bc")
+	assert.Equal(t, text[res[0].Start:res[0].End], "bc")
+	t.Logf("res: %v", res)
+}
+
+func TestAllOverlappingSearch(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.SetMatchKind(AllOverlapping)
+	keys := []string{"he", "she", "his", "hers"}
+	for _, k := range keys {
+		trie.Add(k)
+	}
+	text := "ushers"
+	res := trie.Search(text)
+	expected := []struct {
+		key      string
+		start    int
+		isPrefix bool
+	}{
+		{"she", 1, false},
+		{"he", 2, true},
+		{"hers", 2, false},
+	}
+	assert.Equal(t, len(res), len(expected))
+	for i := 0; i < len(res); i++ {
+		assert.Equal(t, res[i].Key, expected[i].key)
+		assert.Equal(t, res[i].Start, expected[i].start)
+		assert.Equal(t, res[i].IsPrefix, expected[i].isPrefix)
+	}
+	t.Logf("res: %v", res)
+}
+
+func TestAllOverlappingLikeBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	randomString := func(size int) string {
+		letters := []rune("abcé")
+		word := make([]rune, size)
+		for i := range word {
+			word[i] = letters[r.Intn(len(letters))]
+		}
+		return string(word)
+	}
+	for round := 0; round < 50; round++ {
+		trie := NewFlashKeywords(true)
+		trie.SetMatchKind(AllOverlapping)
+		keysSet := make(map[string]bool)
+		for i := 0; i < 10; i++ {
+			key := randomString(1 + r.Intn(4))
+			trie.Add(key)
+			keysSet[key] = true
+		}
+		keys := make([]string, 0, len(keysSet))
+		for k := range keysSet {
+			keys = append(keys, k)
+		}
+		text := randomString(100)
+		res := trie.Search(text)
+		expected := bruteForceSearch(keys, text)
+		assert.Equal(t, len(res), len(expected))
+		for i := 0; i < len(res) && i < len(expected); i++ {
+			assert.Equal(t, res[i].Start, expected[i][0])
+			assert.Equal(t, res[i].End, expected[i][1])
+		}
+	}
+}
+
+func TestAllOverlappingAfterUpdates(t *testing.T) {
+	trie := NewFlashKeywords(false)
+	trie.SetMatchKind(AllOverlapping)
+	trie.Add("abc")
+	assert.Equal(t, len(trie.Search("xABCd")), 1)
+
+	// the links must be rebuilt after each update of the trie
+	trie.Add("bcd")
+	assert.Equal(t, len(trie.Search("xABCd")), 2)
+	trie.RemoveKey("abc")
+	res := trie.Search("xABCd")
+	assert.Equal(t, len(res), 1)
+	assert.Equal(t, res[0].Key, "bcd")
+	trie.RemoveKey("bcd")
+	assert.Equal(t, len(trie.Search("xABCd")), 0)
+}
+
+func TestAllOverlappingBoundaryMode(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.SetMatchKind(AllOverlapping)
+	trie.SetBoundaryMode(true)
+	keys := []string{"new york", "york", "new york city", "city"}
+	for _, k := range keys {
+		trie.Add(k)
+	}
+	res := trie.Search("new york city, yorkshire")
+	found := make([]string, len(res))
+	for i, r := range res {
+		found[i] = r.Key
+	}
+	assert.Equal(t, strings.Join(found, "|"), "new york|york|new york city|city")
+	t.Logf("res: %v", res)
+}
+
+func TestAllOverlappingReplace(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.SetMatchKind(AllOverlapping)
+	trie.AddKeyWord("abcd", "X")
+	trie.AddKeyWord("bc", "Y")
+	// the first key found is replaced and the overlapping ones are skipped
+	assert.Equal(t, trie.Replace("abcx abcd"), "aYx aYd")
+	trie.AddKeyWord("ab", "Z")
+	assert.Equal(t, trie.Replace("abcx abcd"), "Zcx Zcd")
+}
+
+func TestMatchKinds(t *testing.T) {
+	type found struct {
+		key      string
+		start    int
+		isPrefix bool
+	}
+	testdata := []struct {
+		name     string
+		keys     []string
+		text     string
+		expected map[MatchKind][]found
+	}{
+		{
+			name: "InsertShortThenLong",
+			keys: []string{"cat", "catch"},
+			text: "Try to catch this",
+			expected: map[MatchKind][]found{
+				Greedy:          {{"cat", 7, true}, {"catch", 7, false}},
+				AllOverlapping:  {{"cat", 7, true}, {"catch", 7, false}},
+				LeftmostLongest: {{"catch", 7, false}},
+				LeftmostFirst:   {{"cat", 7, true}},
+				Shortest:        {{"cat", 7, true}},
+			},
+		},
+		{
+			name: "InsertLongThenShort",
+			keys: []string{"catch", "cat"},
+			text: "Try to catch this",
+			expected: map[MatchKind][]found{
+				Greedy:          {{"cat", 7, true}, {"catch", 7, false}},
+				AllOverlapping:  {{"cat", 7, true}, {"catch", 7, false}},
+				LeftmostLongest: {{"catch", 7, false}},
+				LeftmostFirst:   {{"catch", 7, false}},
+				Shortest:        {{"cat", 7, true}},
+			},
+		},
+		{
+			name: "GoBackToRootTrick",
+			keys: []string{"chetoos", "055-5647-3456", "chetoosPiza"},
+			text: "call chetoos055-5647-3456 chetoosPiza",
+			expected: map[MatchKind][]found{
+				Greedy: {
+					{"chetoos", 5, false}, {"055-5647-3456", 12, false},
+					{"chetoos", 26, true}, {"chetoosPiza", 26, false},
+				},
+				AllOverlapping: {
+					{"chetoos", 5, false}, {"055-5647-3456", 12, false},
+					{"chetoos", 26, true}, {"chetoosPiza", 26, false},
+				},
+				LeftmostLongest: {
+					{"chetoos", 5, false}, {"055-5647-3456", 12, false}, {"chetoosPiza", 26, false},
+				},
+				LeftmostFirst: {
+					{"chetoos", 5, false}, {"055-5647-3456", 12, false}, {"chetoos", 26, true},
+				},
+				Shortest: {
+					{"chetoos", 5, false}, {"055-5647-3456", 12, false}, {"chetoos", 26, true},
+				},
+			},
+		},
+		{
+			name: "KeyInsideLongerKey",
+			keys: []string{"abcd", "bc", "cde"},
+			text: "abcde",
+			expected: map[MatchKind][]found{
+				Greedy:          {{"abcd", 0, false}},
+				AllOverlapping:  {{"bc", 1, false}, {"abcd", 0, false}, {"cde", 2, false}},
+				LeftmostLongest: {{"abcd", 0, false}},
+				LeftmostFirst:   {{"abcd", 0, false}},
+				Shortest:        {{"bc", 1, false}},
+			},
+		},
+		{
+			name: "LeftmostAcrossOverlaps",
+			keys: []string{"b", "abc", "cd", "bcde"},
+			text: "abcdef",
+			expected: map[MatchKind][]found{
+				Greedy:          {{"abc", 0, false}},
+				AllOverlapping:  {{"b", 1, true}, {"abc", 0, false}, {"cd", 2, false}, {"bcde", 1, false}},
+				LeftmostLongest: {{"abc", 0, false}},
+				LeftmostFirst:   {{"abc", 0, false}},
+				Shortest:        {{"b", 1, true}, {"cd", 2, false}},
+			},
+		},
+	}
+
+	for _, item := range testdata {
+		for kind, expected := range item.expected {
+			trie := NewFlashKeywords(true)
+			trie.SetMatchKind(kind)
+			for _, k := range item.keys {
+				trie.Add(k)
+			}
+			res := trie.Search(item.text)
+			assert.Equal(t, len(res), len(expected), "%v kind=%v", item.name, kind)
+			for i := 0; i < len(res) && i < len(expected); i++ {
+				assert.Equal(t, res[i].Key, expected[i].key, "%v kind=%v", item.name, kind)
+				assert.Equal(t, res[i].Start, expected[i].start, "%v kind=%v", item.name, kind)
+				assert.Equal(t, res[i].IsPrefix, expected[i].isPrefix, "%v kind=%v", item.name, kind)
+				assert.Equal(t, item.text[res[i].Start:res[i].End], res[i].Key)
+			}
+			t.Logf("%v kind=%v res: %v", item.name, kind, res)
+		}
+	}
+}
+
+func TestMatchKindsSearchAndReplaceAgree(t *testing.T) {
+	keys2Clean := map[string]string{
+		"cat":     "CAT",
+		"catch":   "",
+		"at":      "AT",
+		"atch th": "ATCHTH",
+		"his":     "HIS",
+		"this":    "",
+		"is a":    "ISA",
+	}
+	text := "Try to catch this cat, this is a catch"
+	for _, kind := range []MatchKind{Greedy, AllOverlapping, LeftmostLongest, LeftmostFirst, Shortest} {
+		trie := NewFlashKeywords(true)
+		trie.SetMatchKind(kind)
+		for key, clean := range keys2Clean {
+			trie.AddKeyWord(key, clean)
+		}
+		var buf strings.Builder
+		last := 0
+		for _, r := range trie.Search(text) {
+			if r.CleanWord == "" || r.Start < last {
+				continue
+			}
+			buf.WriteString(text[last:r.Start])
+			buf.WriteString(r.CleanWord)
+			last = r.End
+		}
+		buf.WriteString(text[last:])
+		newText := trie.Replace(text)
+		assert.Equal(t, newText, buf.String(), "kind=%v", kind)
+		t.Logf("kind=%v newText: %v", kind, newText)
+	}
+}
+
+func TestLeftmostLongestReplace(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.SetMatchKind(LeftmostLongest)
+	trie.AddKeyWord("java", "lang")
+	trie.AddKeyWord("java programing", "skill")
+	trie.AddKeyWord("programing language", "PL")
+	text := "java programing language, java programing and java"
+	assert.Equal(t, trie.Replace(text), "skill language, skill and lang")
+}
+
+func TestMatchKindsCaseInsensitiveReplace(t *testing.T) {
+	for _, kind := range []MatchKind{Greedy, AllOverlapping, LeftmostLongest, LeftmostFirst, Shortest} {
+		trie := NewFlashKeywords(false)
+		trie.SetMatchKind(kind)
+		trie.AddKeyWord("New York", "NYC")
+		text := "İ love NEW YORK and new york"
+		newText := trie.Replace(text)
+		assert.Equal(t, newText, "İ love NYC and NYC", "kind=%v", kind)
+		res := trie.Search(text)
+		assert.Equal(t, len(res), 2)
+		assert.Equal(t, text[res[0].Start:res[0].End], "NEW YORK")
+		assert.Equal(t, res[0].Key, "new york")
+	}
+}
+
+func TestMatchKindsEmptyKey(t *testing.T) {
+	// the root is a word but the empty key is never found
+	for _, kind := range allMatchKinds {
+		trie := NewFlashKeywords(true)
+		trie.SetMatchKind(kind)
+		trie.AddKeyWord("", "E")
+		trie.AddKeyWord("ab", "X")
+		res := trie.Search("zzab")
+		assert.Equal(t, res, []Result{{Key: "ab", CleanWord: "X", Start: 2, End: 4}}, "kind=%v", kind)
+		assert.Equal(t, trie.Replace("zzab"), "zzX", "kind=%v", kind)
+
+		frozen, err := trie.Freeze()
+		assert.Nil(t, err)
+		assert.Equal(t, frozen.Search("zzab"), res, "kind=%v", kind)
+		assert.Equal(t, frozen.Replace("zzab"), "zzX", "kind=%v", kind)
+	}
+}
+
+func TestMatchKindsConcurrentSearch(t *testing.T) {
+	// the first searches after an update build the links once (see `go test -race`)
+	for _, kind := range allMatchKinds {
+		trie := newStreamTestTrie(kind, true)
+		results := make([][]Result, 4)
+		var wg sync.WaitGroup
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i] = trie.Search(streamTestText)
+			}(i)
+		}
+		wg.Wait()
+		for i := range results {
+			assert.Equal(t, results[i], trie.Search(streamTestText), "kind=%v", kind)
+		}
+		assert.Equal(t, trie.isLinked(), kind != Greedy)
+	}
+}
@@ -0,0 +1,143 @@
+package flashtext
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// the options of `SearchAllWithOptions`:
+//   - `Workers`: the nbr of goroutines searching the documents, `runtime.GOMAXPROCS(0)`
+//     if not positive.
+//   - `Ordered`: the results are given in the order of the documents, otherwise as soon as
+//     they are ready. At most about twice `Workers` documents are held waiting for their turn.
+type BatchOptions struct {
+	Workers int
+	Ordered bool
+}
+
+// BatchResult is the result of the search of a document by `SearchAll`:
+//   - `ID`: the position of the document in the channel of the documents, starting at 0.
+//   - `Results`: the keys found in the document, like `Search`.
+type BatchResult struct {
+	ID      int
+	Results []Result
+}
+
+// SearchAll searches each document received from `docs` with a pool of goroutines sharing the
+// trie, see `SearchAllWithOptions`. The results are given as soon as they are ready
+func (tree *FlashKeywords) SearchAll(ctx context.Context, docs <-chan string) <-chan BatchResult {
+	return tree.SearchAllWithOptions(ctx, docs, BatchOptions{})
+}
+
+// SearchAllWithOptions searches each document received from `docs` with a pool of goroutines
+// sharing the trie and gives their results on the returned channel. The channel is closed
+// once `docs` is closed and all its documents are searched, or as soon as `ctx` is done:
+// the remaining documents are then left unsearched and `ctx.Err()` tells it apart. The
+// channel must be read until it is closed or `ctx` canceled. Like `Search`, the trie must
+// not be updated before the channel is closed
+func (tree *FlashKeywords) SearchAllWithOptions(ctx context.Context, docs <-chan string, opts BatchOptions) <-chan BatchResult {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	// the goroutines only read the trie, `Greedy` doesn't need the links
+	if tree.matchKind != Greedy {
+		tree.ensureLinks()
+	}
+
+	out := make(chan BatchResult, workers)
+	jobs := make(chan batchJob)
+	// ordered: the channels of the results of the documents being searched, in their order
+	var order chan chan BatchResult
+	if opts.Ordered {
+		order = make(chan chan BatchResult, workers)
+	}
+
+	go func() {
+		defer close(jobs)
+		if order != nil {
+			defer close(order)
+		}
+		for id := 0; ; id++ {
+			var job batchJob
+			select {
+			case <-ctx.Done():
+				return
+			case doc, ok := <-docs:
+				if !ok {
+					return
+				}
+				job = batchJob{id: id, doc: doc}
+			}
+			if order != nil {
+				job.done = make(chan BatchResult, 1)
+				select {
+				case <-ctx.Done():
+					return
+				case order <- job.done:
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case jobs <- job:
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				if ctx.Err() != nil {
+					return
+				}
+				res := BatchResult{ID: job.id, Results: tree.Search(job.doc)}
+				if job.done != nil {
+					job.done <- res
+					continue
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case out <- res:
+				}
+			}
+		}()
+	}
+
+	if order == nil {
+		go func() {
+			wg.Wait()
+			close(out)
+		}()
+		return out
+	}
+	go func() {
+		defer close(out)
+		for done := range order {
+			var res BatchResult
+			select {
+			case <-ctx.Done():
+				return
+			case res = <-done:
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- res:
+			}
+		}
+	}()
+	return out
+}
+
+// batchJob is a document to search, `done` receives its result when ordered
+type batchJob struct {
+	id   int
+	doc  string
+	done chan BatchResult
+}
@@ -0,0 +1,113 @@
+package flashtext
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func batchTestDocs(n int) []string {
+	docs := make([]string, n)
+	for i := range docs {
+		docs[i] = fmt.Sprintf("record %d: java programing, %d cats catch the 北京 cat", i, i%7)
+	}
+	return docs
+}
+
+// sendDocs sends the documents on a new channel, closed at the end
+func sendDocs(docs []string) <-chan string {
+	ch := make(chan string)
+	go func() {
+		defer close(ch)
+		for _, doc := range docs {
+			ch <- doc
+		}
+	}()
+	return ch
+}
+
+func TestSearchAll(t *testing.T) {
+	docs := batchTestDocs(500)
+	for _, kind := range allMatchKinds {
+		trie := newStreamTestTrie(kind, true)
+		seen := make([]bool, len(docs))
+		for res := range trie.SearchAll(context.Background(), sendDocs(docs)) {
+			assert.Equal(t, seen[res.ID], false)
+			seen[res.ID] = true
+			assert.Equal(t, res.Results, trie.Search(docs[res.ID]), "kind=%v id=%v", kind, res.ID)
+		}
+		for id := range seen {
+			assert.Equal(t, seen[id], true, id)
+		}
+	}
+}
+
+func TestSearchAllOrdered(t *testing.T) {
+	docs := batchTestDocs(500)
+	trie := newStreamTestTrie(LeftmostLongest, false)
+	for _, workers := range []int{0, 1, 7} {
+		id := 0
+		results := trie.SearchAllWithOptions(context.Background(), sendDocs(docs), BatchOptions{Workers: workers, Ordered: true})
+		for res := range results {
+			assert.Equal(t, res.ID, id)
+			assert.Equal(t, res.Results, trie.Search(docs[id]))
+			id++
+		}
+		assert.Equal(t, id, len(docs))
+	}
+
+	// no document
+	res, ok := <-trie.SearchAll(context.Background(), sendDocs(nil))
+	assert.Equal(t, ok, false)
+	assert.Equal(t, res, BatchResult{})
+}
+
+func TestSearchAllCanceled(t *testing.T) {
+	trie := newStreamTestTrie(Greedy, false)
+	for _, ordered := range []bool{false, true} {
+		ctx, cancel := context.WithCancel(context.Background())
+		// the documents are never closed: the results are closed by the cancellation
+		docs := make(chan string)
+		go func() {
+			for _, doc := range batchTestDocs(1000) {
+				select {
+				case <-ctx.Done():
+					return
+				case docs <- doc:
+				}
+			}
+		}()
+		results := trie.SearchAllWithOptions(ctx, docs, BatchOptions{Workers: 2, Ordered: ordered})
+		count := 0
+		for range results {
+			count++
+			if count == 3 {
+				cancel()
+			}
+		}
+		cancel()
+		assert.Equal(t, count >= 3 && count < 1000, true, "ordered=%v count=%v", ordered, count)
+		assert.Equal(t, ctx.Err(), context.Canceled)
+	}
+}
+
+func TestSearchAllConcurrentGreedy(t *testing.T) {
+	// `Greedy` doesn't build the links: the calls only read the trie (see `go test -race`)
+	trie := newStreamTestTrie(Greedy, true)
+	docs := batchTestDocs(100)
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for res := range trie.SearchAll(context.Background(), sendDocs(docs)) {
+				assert.Equal(t, res.Results, trie.Search(docs[res.ID]), res.ID)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, trie.isLinked(), false)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ayoyu/flashtext"
+)
+
+// test -benchmem -run=^$ -bench 'BenchmarkLoad'
+func BenchmarkLoadFromWords(b *testing.B) {
+	words, _ := readGenWordsTestData(WORDS_FILE_PATH)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		flash := flashtext.NewFlashKeywords(true)
+		for _, word := range words {
+			flash.AddKeyWord(word, "clean")
+		}
+	}
+}
+
+func BenchmarkLoadUnmarshalBinary(b *testing.B) {
+	words, _ := readGenWordsTestData(WORDS_FILE_PATH)
+	flash := flashtext.NewFlashKeywords(true)
+	for _, word := range words {
+		flash.AddKeyWord(word, "clean")
+	}
+	data, err := flash.MarshalBinary()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var decoded flashtext.FlashKeywords
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
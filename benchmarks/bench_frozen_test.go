@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/ayoyu/flashtext"
+)
+
+// test -benchmem -run=^$ -bench 'BenchmarkSearchMapTrie|BenchmarkSearchFrozenTrie'
+func benchmarkSearchTrie(b *testing.B, frozen bool, kind flashtext.MatchKind) {
+	words, _ := readGenWordsTestData(WORDS_FILE_PATH)
+	corpus, _ := readGenCorpusTestData(CORPUS_FILE_PATH)
+	r := rand.New(rand.NewSource(42))
+	for keysSize := 10; keysSize < 20011; keysSize += 5000 {
+		flash := flashtext.NewFlashKeywords(true)
+		flash.SetMatchKind(kind)
+		for i := 0; i < keysSize; i++ {
+			flash.Add(words[r.Intn(len(words))])
+		}
+		search := flash.Search
+		if frozen {
+			frozenFlash, err := flash.Freeze()
+			if err != nil {
+				b.Fatal(err)
+			}
+			search = frozenFlash.Search
+		}
+		search(corpus)
+		b.Run(
+			fmt.Sprintf("key_size=%d", keysSize), func(b *testing.B) {
+				b.SetBytes(int64(len(corpus)))
+				for i := 0; i < b.N; i++ {
+					search(corpus)
+				}
+			},
+		)
+	}
+}
+
+func BenchmarkSearchMapTrie(b *testing.B) {
+	benchmarkSearchTrie(b, false, flashtext.Greedy)
+}
+
+func BenchmarkSearchFrozenTrie(b *testing.B) {
+	benchmarkSearchTrie(b, true, flashtext.Greedy)
+}
+
+func BenchmarkSearchMapTrieLeftmostLongest(b *testing.B) {
+	benchmarkSearchTrie(b, false, flashtext.LeftmostLongest)
+}
+
+func BenchmarkSearchFrozenTrieLeftmostLongest(b *testing.B) {
+	benchmarkSearchTrie(b, true, flashtext.LeftmostLongest)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/ayoyu/flashtext"
+)
+
+// test -benchmem -run=^$ -bench 'BenchmarkSearchSequential|BenchmarkSearchParallel'
+func benchmarkSearchParallel(b *testing.B, workers int) {
+	words, _ := readGenWordsTestData(WORDS_FILE_PATH)
+	corpus, _ := readGenCorpusTestData(CORPUS_FILE_PATH)
+	// a document of several megabytes
+	document := strings.Repeat(corpus, 1+(4<<20)/(len(corpus)+1))
+	r := rand.New(rand.NewSource(42))
+	flash := flashtext.NewFlashKeywords(true)
+	for i := 0; i < 10000; i++ {
+		flash.Add(words[r.Intn(len(words))])
+	}
+	flash.Search(corpus)
+	b.Run(
+		fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.SetBytes(int64(len(document)))
+			for i := 0; i < b.N; i++ {
+				if workers == 1 {
+					flash.Search(document)
+				} else {
+					flash.SearchParallel(document, workers)
+				}
+			}
+		},
+	)
+}
+
+func BenchmarkSearchSequential(b *testing.B) {
+	benchmarkSearchParallel(b, 1)
+}
+
+func BenchmarkSearchParallel(b *testing.B) {
+	benchmarkSearchParallel(b, 4)
+}
@@ -0,0 +1,481 @@
+package flashtext
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"sort"
+	"unicode/utf8"
+)
+
+// The binary dictionary is made of fixed size little endian tables, so it can be read
+// in place without decoding (see `parseBinary`):
+//
+//	header     binaryHeaderSize bytes, see the `binaryHeader` fields
+//	nodes      nbrNodes * binaryNodeSize bytes in breadth-first order, the root first
+//	edges      nbrEdges * binaryEdgeSize bytes, the children of a node are contiguous
+//	           and sorted by rune
+//	wordChars  nbrWordChars * binaryEdgeSize bytes, the overrides of the word characters
+//	strings    stringsLen bytes, the keys and the clean words
+//	padding    up to a multiple of 4 bytes
+//	checksum   CRC-32 (Castagnoli) of all the previous bytes
+const (
+	binaryMagic      = "FLTX"
+	binaryVersion    = 1
+	binaryHeaderSize = 44
+	binaryNodeSize   = 44
+	binaryEdgeSize   = 8
+)
+
+// noNode is a missing failure or output link
+const noNode uint32 = math.MaxUint32
+
+const (
+	binaryCaseSensitive = 1 << iota
+	binaryBoundary
+	binaryTrackPositions
+)
+
+const (
+	binaryIsWord = 1 << iota
+	binaryKeep
+)
+
+var (
+	// ErrBinaryFormat is returned when the data is not a binary dictionary
+	ErrBinaryFormat = errors.New("flashtext: not a binary dictionary")
+	// ErrBinaryVersion is returned for a binary dictionary written by another version of the format
+	ErrBinaryVersion = errors.New("flashtext: unsupported binary dictionary version")
+	// ErrBinaryCorrupted is returned when the checksum or the content of the binary dictionary is invalid
+	ErrBinaryCorrupted = errors.New("flashtext: corrupted binary dictionary")
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+var errTooLarge = errors.New("flashtext: the trie is too large for the binary format")
+
+// tablesFit reports whether the byte sizes of the tables fit in a uint32: the offsets in
+// the tables are computed in uint32 (a node per rune, about 97M nodes at most)
+func tablesFit(nbrNodes, nbrEdges, nbrWordChars uint64) bool {
+	return nbrNodes*binaryNodeSize <= math.MaxUint32 && nbrEdges*binaryEdgeSize <= math.MaxUint32 &&
+		nbrWordChars*binaryEdgeSize <= math.MaxUint32
+}
+
+type binaryHeader struct {
+	version      uint32
+	flags        uint32
+	matchKind    uint32
+	nbrAdded     uint32
+	nbrNodes     uint32
+	nbrEdges     uint32
+	nbrWordChars uint32
+	stringsLen   uint32
+	size         uint32
+	maxDepth     uint32 // nbr of runes of the longest key, the size of the rune window of the scanner
+}
+
+// binaryNode is a node of the `nodes` table, the strings are offsets in the `strings` table
+type binaryNode struct {
+	firstEdge uint32
+	nbrEdges  uint32
+	keyOff    uint32
+	keyLen    uint32
+	cleanOff  uint32
+	cleanLen  uint32
+	order     uint32
+	depth     uint32
+	failure   uint32
+	output    uint32
+	flags     uint32
+}
+
+// MarshalBinary implements the `encoding.BinaryMarshaler` interface: it encodes the trie
+// with its settings (case sensitivity, boundary mode, word characters, `MatchKind`...)
+// and the links of the automaton in a versioned and checksummed format, restored as is by
+// `UnmarshalBinary` without adding the keys one by one
+func (tree *FlashKeywords) MarshalBinary() ([]byte, error) {
+	tree.ensureLinks()
+
+	// the format stores a node per rune: the states of the labels are nodes of their own.
+	// Breadth-first order: the children of a node get consecutive ids,
+	// the edge `e` leads to the node `e+1`
+	order := make([]trieState, 0, tree.nbrNodes)
+	ids := make(map[trieState]uint32, tree.nbrNodes)
+	edges := make([]rune, 0, tree.nbrNodes)
+	order = append(order, trieState{node: tree.root})
+	ids[order[0]] = 0
+	for i := 0; i < len(order); i++ {
+		state := order[i]
+		first := len(edges)
+		if state.pos < len(state.node.label) {
+			edges = append(edges, state.node.label[state.pos])
+		} else {
+			edges = sortedChildren(state.node, edges)
+		}
+		for _, char := range edges[first:] {
+			child, _ := state.next(char)
+			ids[child] = uint32(len(order))
+			order = append(order, child)
+		}
+		if !tablesFit(uint64(len(order)), uint64(len(edges)), 0) {
+			return nil, errTooLarge
+		}
+	}
+	if uint64(tree.nbrAdded) > math.MaxUint32 {
+		return nil, errTooLarge
+	}
+
+	// the clean words are often shared by several keys and written once
+	var stringsTable []byte
+	cleanOffsets := make(map[string]uint32)
+	linkID := func(node *TrieNode) uint32 {
+		if node == nil {
+			return noNode
+		}
+		return ids[trieState{node, len(node.label)}]
+	}
+	nodes := make([]binaryNode, len(order))
+	firstEdge := 0
+	for i, state := range order {
+		n := &nodes[i]
+		n.firstEdge = uint32(firstEdge)
+		n.depth = uint32(state.depth())
+		links := state.links()
+		if links.failure.node == nil {
+			n.failure = noNode
+		} else {
+			n.failure = ids[links.failure]
+		}
+		n.output = linkID(links.output)
+		if state.pos < len(state.node.label) {
+			n.nbrEdges = 1
+			firstEdge++
+			continue
+		}
+		node := state.node
+		n.nbrEdges = uint32(len(node.children))
+		firstEdge += len(node.children)
+		if node.keep {
+			n.flags |= binaryKeep
+		}
+		if !node.isWord {
+			continue
+		}
+		n.flags |= binaryIsWord
+		n.order = uint32(node.order)
+		n.keyOff, n.keyLen = uint32(len(stringsTable)), uint32(len(node.key))
+		stringsTable = append(stringsTable, node.key...)
+		off, ok := cleanOffsets[node.cleanWord]
+		if !ok {
+			off = uint32(len(stringsTable))
+			cleanOffsets[node.cleanWord] = off
+			stringsTable = append(stringsTable, node.cleanWord...)
+		}
+		n.cleanOff, n.cleanLen = off, uint32(len(node.cleanWord))
+		if uint64(len(stringsTable)) > math.MaxUint32 {
+			return nil, errTooLarge
+		}
+	}
+
+	wordChars := make([]rune, 0, len(tree.wordChars))
+	for char := range tree.wordChars {
+		wordChars = append(wordChars, char)
+	}
+	sort.Slice(wordChars, func(i, j int) bool { return wordChars[i] < wordChars[j] })
+	if !tablesFit(uint64(len(order)), uint64(len(edges)), uint64(len(wordChars))) {
+		return nil, errTooLarge
+	}
+
+	var flags uint32
+	if tree.caseSensitive {
+		flags |= binaryCaseSensitive
+	}
+	if tree.boundary {
+		flags |= binaryBoundary
+	}
+	if tree.trackPositions {
+		flags |= binaryTrackPositions
+	}
+
+	tablesSize := binaryHeaderSize + len(nodes)*binaryNodeSize + (len(edges)+len(wordChars))*binaryEdgeSize + len(stringsTable)
+	data := make([]byte, (tablesSize+3)/4*4+4)
+	le := binary.LittleEndian
+	copy(data, binaryMagic)
+	p := data[len(binaryMagic):]
+	put := func(v uint32) {
+		le.PutUint32(p, v)
+		p = p[4:]
+	}
+	for _, v := range []uint32{
+		binaryVersion, flags, uint32(tree.matchKind), uint32(tree.nbrAdded), uint32(len(nodes)),
+		uint32(len(edges)), uint32(len(wordChars)), uint32(len(stringsTable)), uint32(tree.size),
+		uint32(tree.maxDepth),
+	} {
+		put(v)
+	}
+	for _, n := range nodes {
+		for _, v := range []uint32{
+			n.firstEdge, n.nbrEdges, n.keyOff, n.keyLen, n.cleanOff, n.cleanLen,
+			n.order, n.depth, n.failure, n.output, n.flags,
+		} {
+			put(v)
+		}
+	}
+	for e, char := range edges {
+		put(uint32(char))
+		put(uint32(e + 1))
+	}
+	for _, char := range wordChars {
+		put(uint32(char))
+		if tree.wordChars[char] {
+			put(1)
+		} else {
+			put(0)
+		}
+	}
+	copy(p, stringsTable)
+
+	sum := len(data) - 4
+	le.PutUint32(data[sum:], crc32.Checksum(data[:sum], castagnoli))
+	return data, nil
+}
+
+// UnmarshalBinary implements the `encoding.BinaryUnmarshaler` interface: it replaces the
+// content and the settings of the trie by the ones encoded by `MarshalBinary`. Returns an
+// error matching `ErrBinaryFormat`, `ErrBinaryVersion` or `ErrBinaryCorrupted` with `errors.Is`
+// if the data can't be decoded, the trie is then left unchanged
+func (tree *FlashKeywords) UnmarshalBinary(data []byte) error {
+	b, err := parseBinary(data, true)
+	if err != nil {
+		return err
+	}
+
+	decoded := &FlashKeywords{
+		size:           int(b.size),
+		caseSensitive:  b.flags&binaryCaseSensitive != 0,
+		boundary:       b.flags&binaryBoundary != 0,
+		trackPositions: b.flags&binaryTrackPositions != 0,
+		matchKind:      MatchKind(b.matchKind),
+		nbrAdded:       int(b.nbrAdded),
+	}
+	if b.nbrWordChars > 0 {
+		decoded.wordChars = make(map[rune]bool, b.nbrWordChars)
+		for i := uint32(0); i < b.nbrWordChars; i++ {
+			char, isWord := b.wordChar(i)
+			decoded.wordChars[char] = isWord
+		}
+	}
+
+	// the keys and the clean words share the memory of a single string. The chains of
+	// nodes with a single child which are not keys are compressed into labels
+	stringsTable := string(b.strings)
+	compressed := func(i uint32) bool {
+		n := b.node(i)
+		return i != 0 && n.flags&binaryIsWord == 0 && n.nbrEdges == 1
+	}
+	index := make([]uint32, b.nbrNodes)
+	nbrNodes := 0
+	for i := uint32(0); i < b.nbrNodes; i++ {
+		if !compressed(i) {
+			index[i] = uint32(nbrNodes)
+			nbrNodes++
+		}
+	}
+	nodes := make([]TrieNode, nbrNodes)
+	for i := uint32(0); i < b.nbrNodes; i++ {
+		if compressed(i) {
+			continue
+		}
+		n := b.node(i)
+		node := &nodes[index[i]]
+		node.children = make(map[rune]*TrieNode, n.nbrEdges)
+		for e := n.firstEdge; e < n.firstEdge+n.nbrEdges; e++ {
+			char, child := b.edge(e)
+			var label []rune
+			for compressed(child) {
+				var next rune
+				next, child = b.edge(b.node(child).firstEdge)
+				label = append(label, next)
+			}
+			nodes[index[child]].selfRune = char
+			nodes[index[child]].label = label
+			node.children[char] = &nodes[index[child]]
+		}
+		node.depth = int(n.depth)
+		node.keep = n.flags&binaryKeep != 0
+		if n.flags&binaryIsWord != 0 {
+			node.isWord = true
+			node.order = int(n.order)
+			node.key = stringsTable[n.keyOff : n.keyOff+n.keyLen]
+			node.cleanWord = stringsTable[n.cleanOff : n.cleanOff+n.cleanLen]
+		}
+	}
+	// the links are computed again rather than trusted
+	decoded.root = &nodes[0]
+	decoded.nbrNodes = nbrNodes
+
+	*tree = *decoded
+	return nil
+}
+
+// sortedChildren appends the runes of the children of `node` to `runes` in the increasing order
+func sortedChildren(node *TrieNode, runes []rune) []rune {
+	first := len(runes)
+	for char := range node.children {
+		runes = append(runes, char)
+	}
+	children := runes[first:]
+	sort.Slice(children, func(i, j int) bool { return children[i] < children[j] })
+	return runes
+}
+
+// binaryTrie gives access in place to the tables of a binary dictionary
+type binaryTrie struct {
+	binaryHeader
+	nodes     []byte
+	edges     []byte
+	wordChars []byte
+	strings   []byte
+}
+
+func (b *binaryTrie) node(i uint32) binaryNode {
+	p := b.nodes[i*binaryNodeSize : (i+1)*binaryNodeSize]
+	le := binary.LittleEndian
+	return binaryNode{
+		firstEdge: le.Uint32(p[0:]),
+		nbrEdges:  le.Uint32(p[4:]),
+		keyOff:    le.Uint32(p[8:]),
+		keyLen:    le.Uint32(p[12:]),
+		cleanOff:  le.Uint32(p[16:]),
+		cleanLen:  le.Uint32(p[20:]),
+		order:     le.Uint32(p[24:]),
+		depth:     le.Uint32(p[28:]),
+		failure:   le.Uint32(p[32:]),
+		output:    le.Uint32(p[36:]),
+		flags:     le.Uint32(p[40:]),
+	}
+}
+
+// edge returns the rune and the child node of the edge `i`
+func (b *binaryTrie) edge(i uint32) (rune, uint32) {
+	p := b.edges[i*binaryEdgeSize:]
+	return rune(binary.LittleEndian.Uint32(p)), binary.LittleEndian.Uint32(p[4:])
+}
+
+func (b *binaryTrie) wordChar(i uint32) (rune, bool) {
+	p := b.wordChars[i*binaryEdgeSize:]
+	return rune(binary.LittleEndian.Uint32(p)), binary.LittleEndian.Uint32(p[4:]) != 0
+}
+
+func corrupted(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: "+format, append([]interface{}{ErrBinaryCorrupted}, args...)...)
+}
+
+// parseBinary checks the binary dictionary `data` and returns a view on its tables.
+// The checksum is verified when `verifyChecksum` is true, the structure of the trie
+// is always validated so the tables can be walked without bound checks failing
+func parseBinary(data []byte, verifyChecksum bool) (*binaryTrie, error) {
+	b, err := parseHeader(data, verifyChecksum)
+	if err != nil {
+		return nil, err
+	}
+	return b, b.validate()
+}
+
+// parseHeader is `parseBinary` without the validation of the tables: only the header
+// and the sizes of the tables are checked, the content of the tables is trusted
+func parseHeader(data []byte, verifyChecksum bool) (*binaryTrie, error) {
+	if len(data) < len(binaryMagic)+4 || string(data[:len(binaryMagic)]) != binaryMagic {
+		return nil, ErrBinaryFormat
+	}
+	le := binary.LittleEndian
+	if version := le.Uint32(data[4:]); version != binaryVersion {
+		return nil, fmt.Errorf("%w: version %d, expected %d", ErrBinaryVersion, version, binaryVersion)
+	}
+	if len(data) < binaryHeaderSize+4 || len(data)%4 != 0 {
+		return nil, corrupted("truncated data of %d bytes", len(data))
+	}
+	sum := len(data) - 4
+	if verifyChecksum && crc32.Checksum(data[:sum], castagnoli) != le.Uint32(data[sum:]) {
+		return nil, corrupted("checksum mismatch")
+	}
+
+	b := &binaryTrie{}
+	for i, field := range []*uint32{
+		&b.version, &b.flags, &b.matchKind, &b.nbrAdded,
+		&b.nbrNodes, &b.nbrEdges, &b.nbrWordChars, &b.stringsLen, &b.size, &b.maxDepth,
+	} {
+		*field = le.Uint32(data[4+4*i:])
+	}
+	if !tablesFit(uint64(b.nbrNodes), uint64(b.nbrEdges), uint64(b.nbrWordChars)) {
+		return nil, corrupted("tables too large")
+	}
+	tablesSize := uint64(binaryHeaderSize) + uint64(b.nbrNodes)*binaryNodeSize +
+		(uint64(b.nbrEdges)+uint64(b.nbrWordChars))*binaryEdgeSize + uint64(b.stringsLen)
+	if (tablesSize+3)/4*4 != uint64(sum) {
+		return nil, corrupted("size of the tables %d for %d bytes", tablesSize, sum)
+	}
+	if b.nbrNodes == 0 || b.nbrEdges != b.nbrNodes-1 || b.matchKind > uint32(Shortest) {
+		return nil, corrupted("invalid header")
+	}
+	p := data[binaryHeaderSize:]
+	b.nodes, p = p[:b.nbrNodes*binaryNodeSize], p[b.nbrNodes*binaryNodeSize:]
+	b.edges, p = p[:b.nbrEdges*binaryEdgeSize], p[b.nbrEdges*binaryEdgeSize:]
+	b.wordChars, p = p[:b.nbrWordChars*binaryEdgeSize], p[b.nbrWordChars*binaryEdgeSize:]
+	b.strings = p[:b.stringsLen]
+	return b, nil
+}
+
+// validate checks that the tables describe a trie: every node but the root is the child
+// of exactly one node with a greater id (so there are no cycles), the offsets are in bounds
+// and the links of the automaton lead to shallower nodes (so following them terminates)
+func (b *binaryTrie) validate() error {
+	nextChild := uint32(1)
+	size := uint32(0)
+	maxDepth := uint32(0)
+	for i := uint32(0); i < b.nbrNodes; i++ {
+		n := b.node(i)
+		if n.flags&^(binaryIsWord|binaryKeep) != 0 || i == 0 && (n.depth != 0 || n.failure != noNode) ||
+			i != 0 && (n.failure >= b.nbrNodes || b.node(n.failure).depth >= n.depth) ||
+			n.output != noNode && (n.output >= b.nbrNodes || b.node(n.output).depth >= n.depth ||
+				b.node(n.output).flags&binaryIsWord == 0) {
+			return corrupted("invalid node %d", i)
+		}
+		if n.flags&binaryIsWord != 0 {
+			size++
+			if uint64(n.keyOff)+uint64(n.keyLen) > uint64(b.stringsLen) ||
+				uint64(n.cleanOff)+uint64(n.cleanLen) > uint64(b.stringsLen) || n.order >= b.nbrAdded {
+				return corrupted("invalid key of the node %d", i)
+			}
+			if n.depth > maxDepth {
+				maxDepth = n.depth
+			}
+		}
+
+		// breadth-first order: the children are the next nodes not yet reached
+		if n.firstEdge != nextChild-1 || uint64(n.firstEdge)+uint64(n.nbrEdges) > uint64(b.nbrEdges) {
+			return corrupted("invalid edges of the node %d", i)
+		}
+		prev := rune(-1)
+		for e := n.firstEdge; e < n.firstEdge+n.nbrEdges; e++ {
+			char, child := b.edge(e)
+			if child != nextChild || child <= i || char <= prev || !utf8.ValidRune(char) ||
+				b.node(child).depth != n.depth+1 {
+				return corrupted("invalid edge %d of the node %d", e, i)
+			}
+			prev = char
+			nextChild++
+		}
+	}
+	if nextChild != b.nbrNodes || size != b.size || maxDepth != b.maxDepth {
+		return corrupted("invalid number of nodes or keys")
+	}
+	for i := uint32(0); i < b.nbrWordChars; i++ {
+		if char, _ := b.wordChar(i); !utf8.ValidRune(char) {
+			return corrupted("invalid word char %d", i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package flashtext
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*FlashKeywords)(nil)
+	_ encoding.BinaryUnmarshaler = (*FlashKeywords)(nil)
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	for _, kind := range allMatchKinds {
+		for _, boundary := range []bool{false, true} {
+			trie := newStreamTestTrie(kind, boundary)
+			trie.AddWordChars("+")
+			trie.RemoveWordChars("_")
+			trie.SetTrackPositions(true)
+			trie.RemoveKey("atc")
+			data, err := trie.MarshalBinary()
+			assert.Nil(t, err)
+
+			decoded := NewFlashKeywords(true)
+			err = decoded.UnmarshalBinary(data)
+			assert.Nil(t, err)
+			assert.Equal(t, decoded.Size(), trie.Size())
+			assert.Equal(t, decoded.nbrNodes, trie.nbrNodes)
+			assert.Equal(t, decoded.GetAllKeywords(), trie.GetAllKeywords())
+			assert.Equal(t, decoded.MatchKind(), kind)
+			assert.Equal(t, decoded.BoundaryMode(), boundary)
+			assert.Equal(t, decoded.wordChars, trie.wordChars)
+			assert.Equal(t, decoded.Search(streamTestText), trie.Search(streamTestText), "kind=%v boundary=%v", kind, boundary)
+			assert.Equal(t, decoded.Replace(streamTestText), trie.Replace(streamTestText))
+
+			// the same trie gives the same bytes
+			again, err := decoded.MarshalBinary()
+			assert.Nil(t, err)
+			assert.Equal(t, again, data)
+		}
+	}
+}
+
+func TestBinaryUpdateAfterUnmarshal(t *testing.T) {
+	trie := NewFlashKeywords(false)
+	trie.AddKeyWord("cat", "CAT")
+	trie.AddKeyWord("catch", "CATCH")
+	trie.SetMatchKind(LeftmostFirst)
+	data, err := trie.MarshalBinary()
+	assert.Nil(t, err)
+
+	decoded := NewFlashKeywords(true)
+	assert.Nil(t, decoded.UnmarshalBinary(data))
+	// the insertion order is kept: `cat` added first wins
+	assert.Equal(t, decoded.Replace("catch"), "CATch")
+	decoded.AddKeyWord("dog", "DOG")
+	assert.Equal(t, decoded.RemoveKey("cat"), true)
+	assert.Equal(t, decoded.Replace("Catch the dog"), "CATCH the DOG")
+	assert.Equal(t, decoded.Size(), 2)
+	assert.Equal(t, decoded.Contains("dog"), true)
+}
+
+func TestBinaryEmptyTrie(t *testing.T) {
+	data, err := NewFlashKeywords(true).MarshalBinary()
+	assert.Nil(t, err)
+	decoded := NewFlashKeywords(false)
+	assert.Nil(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, decoded.Size(), 0)
+	assert.Equal(t, len(decoded.Search("text")), 0)
+	decoded.Add("Text")
+	assert.Equal(t, len(decoded.Search("text")), 0)
+}
+
+func TestBinaryErrors(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.AddKeyWord("java", "lang")
+	trie.AddKeyWord("javascript", "lang")
+	data, err := trie.MarshalBinary()
+	assert.Nil(t, err)
+
+	// with a valid checksum so the content is validated
+	withChecksum := func(data []byte) []byte {
+		sum := len(data) - 4
+		binary.LittleEndian.PutUint32(data[sum:], crc32.Checksum(data[:sum], castagnoli))
+		return data
+	}
+	modified := func(offset int, value uint32) []byte {
+		b := append([]byte(nil), data...)
+		binary.LittleEndian.PutUint32(b[offset:], value)
+		return b
+	}
+	// first edge of the root, the format stores a node per rune of the keys
+	nbrNodes := int(binary.LittleEndian.Uint32(data[20:]))
+	assert.Equal(t, nbrNodes, trie.Stats().Runes+1)
+	edges := binaryHeaderSize + nbrNodes*binaryNodeSize
+
+	testdata := []struct {
+		name     string
+		data     []byte
+		expected error
+	}{
+		{"Empty", nil, ErrBinaryFormat},
+		{"Magic", append([]byte("GOB!"), data[4:]...), ErrBinaryFormat},
+		{"Version", modified(4, binaryVersion+1), ErrBinaryVersion},
+		{"Truncated", data[:len(data)-8], ErrBinaryCorrupted},
+		{"Checksum", modified(binaryHeaderSize+4, 7), ErrBinaryCorrupted},
+		{"NbrNodes", withChecksum(modified(20, 3)), ErrBinaryCorrupted},
+		{"TablesTooLarge", withChecksum(modified(20, 100_000_000)), ErrBinaryCorrupted},
+		{"MatchKind", withChecksum(modified(12, 42)), ErrBinaryCorrupted},
+		{"MaxDepth", withChecksum(modified(40, 1)), ErrBinaryCorrupted},
+		{"EdgeCycle", withChecksum(modified(edges+4, 0)), ErrBinaryCorrupted},
+		{"InvalidRune", withChecksum(modified(edges, 0xD800)), ErrBinaryCorrupted},
+		{"KeyOutOfBounds", withChecksum(modified(binaryHeaderSize+4*binaryNodeSize+12, 1000)), ErrBinaryCorrupted},
+	}
+	for _, item := range testdata {
+		decoded := NewFlashKeywords(true)
+		decoded.Add("kept")
+		err := decoded.UnmarshalBinary(item.data)
+		assert.Equal(t, errors.Is(err, item.expected), true, "%v: %v", item.name, err)
+		// the trie is left unchanged
+		assert.Equal(t, decoded.GetAllKeywords(), map[string]string{"kept": ""})
+		t.Logf("%v: %v", item.name, err)
+	}
+}
+
+func TestBinaryTablesFit(t *testing.T) {
+	// the offsets in the tables are computed in uint32
+	assert.Equal(t, tablesFit(1000, 999, 10), true)
+	assert.Equal(t, tablesFit(math.MaxUint32/binaryNodeSize, math.MaxUint32/binaryNodeSize-1, 0), true)
+	assert.Equal(t, tablesFit(math.MaxUint32/binaryNodeSize+1, 1000, 0), false)
+	assert.Equal(t, tablesFit(1000, math.MaxUint32/binaryEdgeSize+1, 0), false)
+	assert.Equal(t, tablesFit(1000, 999, math.MaxUint32/binaryEdgeSize+1), false)
+}
@@ -0,0 +1,62 @@
+package flashtext
+
+import "unicode"
+
+// Enable or disable the word boundary mode. When enabled a key is only
+// reported (or replaced) if it is delimited on both sides by a non-word
+// character or by the start/end of the text, the same way the `\b` of a
+// regex would do: the key `java` matches in "I like java." but not in
+// "javascript" or "pjava"
+func (tree *FlashKeywords) SetBoundaryMode(enabled bool) {
+	tree.boundary = enabled
+}
+
+// Returns true if the word boundary mode is enabled
+func (tree *FlashKeywords) BoundaryMode() bool {
+	return tree.boundary
+}
+
+// Add the runes of `chars` to the set of the word characters used by the boundary
+// mode. By default the word characters are the Unicode letters, digits and underscore,
+// adding `+` and `#` allows the keys "c++" and "c#" to match in "I know c++." but not in "c+++"
+func (tree *FlashKeywords) AddWordChars(chars string) {
+	tree.setWordChars(chars, true)
+}
+
+// Remove the runes of `chars` from the set of the word characters used by the boundary
+// mode, the removed runes are then considered as separators between words
+func (tree *FlashKeywords) RemoveWordChars(chars string) {
+	tree.setWordChars(chars, false)
+}
+
+func (tree *FlashKeywords) setWordChars(chars string, isWord bool) {
+	if tree.wordChars == nil {
+		tree.wordChars = make(map[rune]bool)
+	}
+	for _, char := range chars {
+		if defaultWordRune(char) == isWord {
+			// back to the default behaviour, no need to keep an override
+			delete(tree.wordChars, char)
+		} else {
+			tree.wordChars[char] = isWord
+		}
+	}
+}
+
+// defaultWordRune reports whether `r` is a word character by default: Unicode letters, digits and underscore
+func defaultWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// isWordRune reports whether `r` is part of a word taking into account
+// the runes added or removed with `AddWordChars` and `RemoveWordChars`
+func (tree *FlashKeywords) isWordRune(r rune) bool {
+	return isWordRuneWith(tree.wordChars, r)
+}
+
+func isWordRuneWith(wordChars map[rune]bool, r rune) bool {
+	if isWord, ok := wordChars[r]; ok {
+		return isWord
+	}
+	return defaultWordRune(r)
+}
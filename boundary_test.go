@@ -0,0 +1,181 @@
+package flashtext
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoundaryModeSearch(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.SetBoundaryMode(true)
+	trie.AddKeyWord("java", "JAVA")
+	testdata := []struct {
+		text  string
+		count int
+	}{
+		{"java", 1},
+		{"I like java.", 1},
+		{"java, java and java!", 3},
+		{"javascript", 0},
+		{"pjava", 0},
+		{"java_2e", 0},
+		{"java2", 0},
+		{"(java)", 1},
+	}
+	for _, item := range testdata {
+		res := trie.Search(item.text)
+		assert.Equal(t, len(res), item.count, item.text)
+		for _, r := range res {
+			assert.Equal(t, item.text[r.Start:r.End], "java")
+		}
+		t.Logf("text: %v res: %v", item.text, res)
+	}
+}
+
+func TestBoundaryModeLikeRegex(t *testing.T) {
+	// the same `\b` regex used in benchmarks/bench_with_regex_test.go
+	keys := []string{"foo", "bar baz", "qux1", "a_b"}
+	texts := []string{
+		"foo bar baz qux1",
+		"foobar bar bazz qux12 a_b",
+		"xfoo foo_ bar baz, (foo) qux1.",
+		"a_b_c a_b-c bar  baz bar baz",
+		"foo;foo;foofoo;foo",
+	}
+	trie := NewFlashKeywords(true)
+	trie.SetBoundaryMode(true)
+	keysWords := make([]string, len(keys))
+	for i, k := range keys {
+		trie.Add(k)
+		keysWords[i] = `\b` + regexp.QuoteMeta(k) + `\b`
+	}
+	reCompile := regexp.MustCompile(strings.Join(keysWords, "|"))
+
+	for _, text := range texts {
+		res := trie.Search(text)
+		expected := reCompile.FindAllStringIndex(text, -1)
+		assert.Equal(t, len(res), len(expected), text)
+		for i := 0; i < len(res) && i < len(expected); i++ {
+			assert.Equal(t, res[i].Start, expected[i][0])
+			assert.Equal(t, res[i].End, expected[i][1])
+		}
+		t.Logf("text: %v res: %v", text, res)
+	}
+}
+
+func TestBoundaryModePrefixKeys(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.SetBoundaryMode(true)
+	trie.AddKeyWord("java", "lang")
+	trie.AddKeyWord("java programing", "skill")
+	text := "java programing javax java programingg"
+	res := trie.Search(text)
+	expected := []struct {
+		key      string
+		isPrefix bool
+	}{
+		{"java", true},
+		{"java programing", false},
+		{"java", true},
+	}
+	assert.Equal(t, len(res), len(expected))
+	for i := 0; i < len(res); i++ {
+		assert.Equal(t, res[i].Key, expected[i].key)
+		assert.Equal(t, res[i].IsPrefix, expected[i].isPrefix)
+	}
+	t.Logf("res: %v", res)
+}
+
+func TestBoundaryModeRetryBrokenWalk(t *testing.T) {
+	// the walk of `java script` breaks on the second `java` which must be found
+	trie := NewFlashKeywords(true)
+	trie.SetBoundaryMode(true)
+	trie.Add("java script")
+	trie.Add("java")
+	res := trie.Search("java java")
+	assert.Equal(t, len(res), 2)
+	assert.Equal(t, res[1].Start, 5)
+	t.Logf("res: %v", res)
+}
+
+func TestBoundaryModeReplace(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.AddKeyWord("java", "python")
+	text := "javascript is not java, pjava"
+	assert.Equal(t, trie.Replace(text), "pythonscript is not python, ppython")
+
+	trie.SetBoundaryMode(true)
+	assert.Equal(t, trie.BoundaryMode(), true)
+	newText := trie.Replace(text)
+	assert.Equal(t, newText, "javascript is not python, pjava")
+	t.Logf("newText: %v", newText)
+}
+
+func TestBoundaryModeUnicode(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.SetBoundaryMode(true)
+	trie.AddKeyWord("café", "coffee")
+	assert.Equal(t, trie.Replace("un café, des cafés"), "un coffee, des cafés")
+	assert.Equal(t, len(trie.Search("écafé")), 0)
+}
+
+func TestAddWordChars(t *testing.T) {
+	trie := NewFlashKeywords(false)
+	trie.SetBoundaryMode(true)
+	trie.AddWordChars("+#")
+	trie.AddKeyWord("c++", "cpp")
+	trie.AddKeyWord("C#", "csharp")
+	testdata := []struct {
+		text  string
+		count int
+	}{
+		{"I know c++.", 1},
+		{"I know c+++", 0},
+		{"c++c", 0},
+		{"C# and c++", 2},
+		{"C##", 0},
+	}
+	for _, item := range testdata {
+		res := trie.Search(item.text)
+		assert.Equal(t, len(res), item.count, item.text)
+		t.Logf("text: %v res: %v", item.text, res)
+	}
+	assert.Equal(t, trie.Replace("c++, c#, c+++"), "cpp, csharp, c+++")
+}
+
+func TestAddWordCharsDot(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.SetBoundaryMode(true)
+	trie.Add("node")
+	trie.Add("node.js")
+	res := trie.Search("node.js")
+	// `.` is not a word char: `node` is a whole word followed by `.js`
+	assert.Equal(t, len(res), 2)
+	assert.Equal(t, res[0].Key, "node")
+
+	trie.AddWordChars(".")
+	res = trie.Search("node.js")
+	assert.Equal(t, len(res), 1)
+	assert.Equal(t, res[0].Key, "node.js")
+	assert.Equal(t, len(trie.Search("node.jsx")), 0)
+	t.Logf("res: %v", res)
+}
+
+func TestRemoveWordChars(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.SetBoundaryMode(true)
+	trie.Add("snake")
+	assert.Equal(t, len(trie.Search("snake_case")), 0)
+
+	trie.RemoveWordChars("_")
+	assert.Equal(t, len(trie.Search("snake_case")), 1)
+	assert.Equal(t, trie.isWordRune('_'), false)
+
+	// back to the default set
+	trie.AddWordChars("_")
+	assert.Equal(t, len(trie.wordChars), 0)
+	assert.Equal(t, len(trie.Search("snake_case")), 0)
+}
@@ -0,0 +1,51 @@
+package flashtext
+
+import "unicode/utf8"
+
+// Search in the byte slice `text` for the stored keys in the trie, same as `Search`
+// without converting the bytes to a string. The `Start` and `End` of the results
+// are byte offsets in `text`.
+func (tree *FlashKeywords) SearchBytes(text []byte) []Result {
+	var res []Result
+	tree.matchesBytes(text, func(m match) {
+		res = append(res, m.result())
+	})
+	if tree.trackPositions {
+		locate(string(text), res)
+	}
+	return res
+}
+
+// Replace the keys found in `src` with their `cleanWord` like `Replace` and appends
+// the new text to `dst`, returning the extended slice like the `append` built-in.
+// No allocation is made when `dst` has enough capacity: the state of the automaton is
+// reused from a call to the next. `dst` and `src` must not overlap.
+func (tree *FlashKeywords) ReplaceBytes(dst, src []byte) []byte {
+	lastChange := 0
+	tree.matchesBytes(src, func(m match) {
+		if skipReplace(m, m.node.cleanWord, lastChange) {
+			return
+		}
+		dst = append(dst, src[lastChange:m.start]...)
+		dst = append(dst, m.node.cleanWord...)
+		lastChange = m.end
+	})
+	return append(dst, src[lastChange:]...)
+}
+
+// matchesBytes is the same as `matches` for a byte slice, the scanner is taken from
+// `scannerPool`
+func (tree *FlashKeywords) matchesBytes(text []byte, fn func(m match)) {
+	s := scannerPool.Get().(*scanner)
+	defer scannerPool.Put(s)
+	tree.resetScanner(s)
+	for idx := 0; idx < len(text); {
+		char, size := rune(text[idx]), 1
+		if char >= utf8.RuneSelf {
+			char, size = utf8.DecodeRune(text[idx:])
+		}
+		s.step(char, idx, size, fn)
+		idx += size
+	}
+	s.finish(len(text), fn)
+}
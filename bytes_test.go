@@ -0,0 +1,74 @@
+package flashtext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBytesLikeString(t *testing.T) {
+	type testCase struct {
+		name string
+		trie *FlashKeywords
+		text string
+	}
+	var testdata []testCase
+	for _, kind := range allMatchKinds {
+		for _, boundary := range []bool{false, true} {
+			testdata = append(testdata, testCase{"stream", newStreamTestTrie(kind, boundary), streamTestText})
+		}
+	}
+	goBackToRoot := NewFlashKeywords(true)
+	goBackToRoot.AddKeyWord("chetoos", "CH")
+	goBackToRoot.Add("055-5647-3456")
+	goBackToRoot.AddKeyWord("chetoosPiza", "CHP")
+	testdata = append(testdata, testCase{"GoBackToRoot", goBackToRoot, "call chetoos055-5647-3456 chetoosPiza"})
+	unicodeCase := NewFlashKeywords(false)
+	unicodeCase.AddKeyWord("İUseUnicode", "U")
+	unicodeCase.AddKeyWord("straße", "street")
+	testdata = append(testdata, testCase{"UnicodeCase", unicodeCase, "İUseUnicode in STRASSE or Straße"})
+	positions := NewFlashKeywords(true)
+	positions.SetTrackPositions(true)
+	positions.AddKeyWord("北京", "Beijing")
+	testdata = append(testdata, testCase{"Positions", positions, "line\n北京 and 北京"})
+
+	for _, item := range testdata {
+		kind := item.trie.MatchKind()
+		assert.Equal(t, item.trie.SearchBytes([]byte(item.text)), item.trie.Search(item.text), "%v kind=%v", item.name, kind)
+		newText := item.trie.ReplaceBytes(nil, []byte(item.text))
+		assert.Equal(t, string(newText), item.trie.Replace(item.text), "%v kind=%v", item.name, kind)
+		t.Logf("%v kind=%v newText: %s", item.name, kind, newText)
+	}
+}
+
+func TestReplaceBytesAppends(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.AddKeyWord("java", "python")
+	dst := []byte("out: ")
+	dst = trie.ReplaceBytes(dst, []byte("I love java"))
+	assert.Equal(t, string(dst), "out: I love python")
+	assert.Equal(t, string(trie.ReplaceBytes(nil, nil)), "")
+}
+
+func TestReplaceBytesNoAllocs(t *testing.T) {
+	src := []byte("Java programing language, javascript and JAVA")
+	for _, kind := range allMatchKinds {
+		trie := NewFlashKeywords(false)
+		trie.SetMatchKind(kind)
+		trie.AddKeyWord("java", "python")
+		trie.AddKeyWord("java programing", "skill")
+		dst := make([]byte, 0, 2*len(src))
+		// the state of the automaton is reused from a call to the next
+		allocs := testing.AllocsPerRun(100, func() {
+			dst = trie.ReplaceBytes(dst[:0], src)
+		})
+		if !raceEnabled {
+			assert.Equal(t, allocs, float64(0), "kind=%v", kind)
+		}
+		assert.Equal(t, string(dst), trie.Replace(string(src)), "kind=%v", kind)
+	}
+	trie := NewFlashKeywords(false)
+	trie.AddKeyWord("java", "python")
+	trie.AddKeyWord("java programing", "skill")
+	assert.Equal(t, string(trie.ReplaceBytes(nil, src)), "python programing language, pythonscript and python")
+}
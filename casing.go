@@ -0,0 +1,80 @@
+package flashtext
+
+import (
+	"strings"
+	"unicode"
+)
+
+// casePattern is the casing of the letters of a string
+type casePattern int
+
+const (
+	mixedCase casePattern = iota // none of the patterns below
+	lowerCase                    // "java"
+	upperCase                    // "JAVA"
+	titleCase                    // "Java", "New York"
+)
+
+// detectCase returns the casing pattern of the letters of `s`, the non letters are ignored
+func detectCase(s string) casePattern {
+	var nbrLetters, nbrUpper, nbrLower int
+	isTitle := true
+	wordStart := true
+	for _, char := range s {
+		if !unicode.IsLetter(char) {
+			wordStart = true
+			continue
+		}
+		nbrLetters++
+		if unicode.IsUpper(char) {
+			nbrUpper++
+			if !wordStart {
+				isTitle = false
+			}
+		} else {
+			if unicode.IsLower(char) {
+				nbrLower++
+			}
+			if wordStart {
+				isTitle = false
+			}
+		}
+		wordStart = false
+	}
+
+	switch {
+	case nbrLetters == 0:
+		return mixedCase
+	case nbrLower == nbrLetters:
+		return lowerCase
+	case nbrUpper == nbrLetters && nbrLetters > 1:
+		return upperCase
+	case isTitle:
+		return titleCase
+	}
+	return mixedCase
+}
+
+// applyCase returns `s` written with the casing `pattern`, unchanged for `mixedCase`
+func applyCase(s string, pattern casePattern) string {
+	switch pattern {
+	case lowerCase:
+		return strings.ToLower(s)
+	case upperCase:
+		return strings.ToUpper(s)
+	case titleCase:
+		wordStart := true
+		return strings.Map(func(char rune) rune {
+			if !unicode.IsLetter(char) {
+				wordStart = true
+				return char
+			}
+			if wordStart {
+				wordStart = false
+				return unicode.ToUpper(char)
+			}
+			return unicode.ToLower(char)
+		}, s)
+	}
+	return s
+}
@@ -0,0 +1,61 @@
+package flashtext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectCase(t *testing.T) {
+	testdata := []struct {
+		text    string
+		pattern casePattern
+	}{
+		{"java", lowerCase},
+		{"JAVA", upperCase},
+		{"Java", titleCase},
+		{"J", titleCase},
+		{"New York", titleCase},
+		{"NEW YORK", upperCase},
+		{"new-york", lowerCase},
+		{"jAvA", mixedCase},
+		{"New york", mixedCase},
+		{"İSTANBUL", upperCase},
+		{"été", lowerCase},
+		{"C3PO", upperCase},
+		{"123", mixedCase},
+		{"北京", mixedCase},
+	}
+	for _, item := range testdata {
+		assert.Equal(t, detectCase(item.text), item.pattern, item.text)
+	}
+}
+
+func TestReplacePreserveCase(t *testing.T) {
+	trie := NewFlashKeywords(false)
+	trie.AddKeyWord("java", "python")
+	trie.AddKeyWord("new york", "big apple")
+	trie.AddKeyWord("ios", "iPadOS")
+	opts := ReplaceOptions{PreserveCase: true}
+	testdata := []struct {
+		text    string
+		newText string
+	}{
+		{"JAVA", "PYTHON"},
+		{"Java", "Python"},
+		{"java", "python"},
+		{"jAvA", "python"},
+		{"I love New York", "I love Big Apple"},
+		{"I LOVE NEW YORK", "I LOVE BIG APPLE"},
+		{"new york, New york", "big apple, big apple"},
+		{"IOS and ios and iOS", "IPADOS and ipados and iPadOS"},
+	}
+	for _, item := range testdata {
+		newText := trie.ReplaceWithOptions(item.text, opts)
+		assert.Equal(t, newText, item.newText)
+		t.Logf("text: %v newText: %v", item.text, newText)
+	}
+	// the default replace writes the cleanWord as it was added
+	assert.Equal(t, trie.Replace("JAVA"), "python")
+	assert.Equal(t, trie.ReplaceWithOptions("JAVA", ReplaceOptions{}), "python")
+}
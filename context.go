@@ -0,0 +1,63 @@
+package flashtext
+
+import (
+	"context"
+	"unicode/utf8"
+)
+
+// contextCheckInterval is the nbr of bytes of the text walked between two checks of the context
+const contextCheckInterval = 4 << 10
+
+// SearchContext is the same as `Search` stopped when `ctx` is done, checked periodically
+// during the walk of the text. Returns the keys found so far with `ctx.Err()`: they are the
+// first results of `Search`, without the keys waiting for the next runes of the text to be
+// reported (a key which may be the prefix of a longer one...)
+func (tree *FlashKeywords) SearchContext(ctx context.Context, text string) ([]Result, error) {
+	var res []Result
+	err := tree.matchesContext(ctx, text, func(m match) {
+		res = append(res, m.result())
+	})
+	if tree.trackPositions {
+		locate(text, res)
+	}
+	return res, err
+}
+
+// ReplaceContext is the same as `Replace` stopped when `ctx` is done, checked periodically
+// during the walk of the text. Returns the text with the keys found so far replaced (see
+// `SearchContext`) and the rest of the text untouched, with `ctx.Err()`
+func (tree *FlashKeywords) ReplaceContext(ctx context.Context, text string) (string, error) {
+	var err error
+	newText := replaceMatches(text, ReplaceOptions{}, nodeCleanWord, func(fn func(m match)) {
+		err = tree.matchesContext(ctx, text, fn)
+	})
+	return newText, err
+}
+
+// matchesContext is the same as `matches` stopped when `ctx` is done
+func (tree *FlashKeywords) matchesContext(ctx context.Context, text string, fn func(m match)) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	done := ctx.Done()
+	s := tree.newScanner()
+	check := contextCheckInterval
+	for idx := 0; idx < len(text); {
+		if idx >= check {
+			select {
+			case <-done:
+				return ctx.Err()
+			default:
+			}
+			check = idx + contextCheckInterval
+		}
+		char, size := rune(text[idx]), 1
+		if char >= utf8.RuneSelf {
+			char, size = utf8.DecodeRuneInString(text[idx:])
+		}
+		s.step(char, idx, size, fn)
+		idx += size
+	}
+	s.finish(len(text), fn)
+	return nil
+}
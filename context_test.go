@@ -0,0 +1,82 @@
+package flashtext
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// lateContext is done from the start but only tells it from the second call to `Err`,
+// so the walk of the text starts and is stopped at the first check
+type lateContext struct {
+	context.Context
+	nbrErr int
+}
+
+func (c *lateContext) Done() <-chan struct{} {
+	done := make(chan struct{})
+	close(done)
+	return done
+}
+
+func (c *lateContext) Err() error {
+	c.nbrErr++
+	if c.nbrErr == 1 {
+		return nil
+	}
+	return context.Canceled
+}
+
+func TestSearchContextLikeSearch(t *testing.T) {
+	for _, kind := range allMatchKinds {
+		for _, boundary := range []bool{false, true} {
+			trie := newStreamTestTrie(kind, boundary)
+			trie.SetTrackPositions(true)
+			res, err := trie.SearchContext(context.Background(), streamTestText)
+			assert.Nil(t, err)
+			assert.Equal(t, res, trie.Search(streamTestText), "kind=%v boundary=%v", kind, boundary)
+			newText, err := trie.ReplaceContext(context.Background(), streamTestText)
+			assert.Nil(t, err)
+			assert.Equal(t, newText, trie.Replace(streamTestText), "kind=%v boundary=%v", kind, boundary)
+		}
+	}
+}
+
+func TestSearchContextCanceled(t *testing.T) {
+	trie := newStreamTestTrie(LeftmostLongest, false)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res, err := trie.SearchContext(ctx, streamTestText)
+	assert.Equal(t, err, context.Canceled)
+	assert.Equal(t, len(res), 0)
+	newText, err := trie.ReplaceContext(ctx, streamTestText)
+	assert.Equal(t, err, context.Canceled)
+	assert.Equal(t, newText, streamTestText)
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	_, err = trie.SearchContext(ctx, streamTestText)
+	assert.Equal(t, err, context.DeadlineExceeded)
+}
+
+func TestSearchContextPartialResults(t *testing.T) {
+	text := strings.Repeat(streamTestText+"\n", 500)
+	for _, kind := range allMatchKinds {
+		trie := newStreamTestTrie(kind, true)
+		expected := trie.Search(text)
+		res, err := trie.SearchContext(&lateContext{Context: context.Background()}, text)
+		assert.Equal(t, err, context.Canceled)
+		// stopped after the first bytes: the first results of `Search`
+		assert.Equal(t, len(res) > 0 && len(res) < len(expected), true, "kind=%v", kind)
+		assert.Equal(t, res, expected[:len(res)], "kind=%v", kind)
+
+		newText, err := trie.ReplaceContext(&lateContext{Context: context.Background()}, text)
+		assert.Equal(t, err, context.Canceled)
+		assert.Equal(t, strings.HasPrefix(trie.Replace(text), newText[:1000]), true, "kind=%v", kind)
+		assert.Equal(t, strings.HasSuffix(newText, text[len(text)-10000:]), true, "kind=%v", kind)
+	}
+}
@@ -0,0 +1,172 @@
+package flashtext
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// MinimizedKeywords is a read-only copy of the keys of a `FlashKeywords` made by `Minimize`.
+// The trie is minimized into a directed acyclic word graph (DAWG): the nodes followed by the
+// same keys endings are merged, so the suffixes shared by many keys ("manager", "engineer",
+// "managers", "engineers") are stored once like the prefixes. A node doesn't identify a key
+// anymore: the keys are numbered in the increasing order and the number of a key, counted
+// during the walk, gives its `cleanWord`. It is safe for concurrent use.
+type MinimizedKeywords struct {
+	nodes      []dawgNode
+	root       int
+	cleanWords []string // the clean words of the keys in the increasing order of the keys
+	trieNodes  int      // nbr of nodes of the trie, see `ReductionRatio`
+}
+
+type dawgNode struct {
+	isWord  bool
+	nbrKeys int        // nbr of the keys ending at the node or after it
+	edges   []dawgEdge // sorted by rune
+}
+
+// dawgEdge leads to the node `child` through the runes `char` and `label`, see radix.go
+type dawgEdge struct {
+	char  rune
+	label []rune
+	child int
+}
+
+// Minimize returns a minimized copy of the keys of the trie with their `cleanWord`, see
+// `MinimizedKeywords`. The trie can still be updated after, the updates are not seen by
+// the `MinimizedKeywords`
+func (tree *FlashKeywords) Minimize() *MinimizedKeywords {
+	m := &MinimizedKeywords{
+		cleanWords: make([]string, 0, tree.size),
+		trieNodes:  tree.nbrNodes,
+	}
+	// the nodes with the same signature are followed by the same keys endings
+	ids := make(map[string]int, tree.nbrNodes)
+	var signature []byte
+	var minimize func(node *TrieNode) int
+	minimize = func(node *TrieNode) int {
+		n := dawgNode{isWord: node.isWord}
+		if node.isWord {
+			n.nbrKeys = 1
+			m.cleanWords = append(m.cleanWords, node.cleanWord)
+		}
+		chars := sortedChildren(node, nil)
+		n.edges = make([]dawgEdge, len(chars))
+		for i, char := range chars {
+			child := node.children[char]
+			label := append([]rune(nil), child.label...)
+			n.edges[i] = dawgEdge{char: char, label: label, child: minimize(child)}
+			n.nbrKeys += m.nodes[n.edges[i].child].nbrKeys
+		}
+
+		signature = strconv.AppendBool(signature[:0], n.isWord)
+		for _, e := range n.edges {
+			signature = strconv.AppendInt(append(signature, ';'), int64(e.char), 10)
+			for _, char := range e.label {
+				signature = strconv.AppendInt(append(signature, ','), int64(char), 10)
+			}
+			signature = strconv.AppendInt(append(signature, '>'), int64(e.child), 10)
+		}
+		if id, ok := ids[string(signature)]; ok {
+			return id
+		}
+		ids[string(signature)] = len(m.nodes)
+		m.nodes = append(m.nodes, n)
+		return len(m.nodes) - 1
+	}
+	m.root = minimize(tree.root)
+	return m
+}
+
+// Returns the number of the keys inside the keys dictionary
+func (m *MinimizedKeywords) Size() int {
+	return len(m.cleanWords)
+}
+
+// Returns the statistics about the size of the graph, see `FlashKeywords.Stats`.
+// The nodes are reached by several edges: `Edges` may be greater than `Nodes - 1`
+func (m *MinimizedKeywords) Stats() TrieStats {
+	stats := TrieStats{Nodes: len(m.nodes)}
+	for _, n := range m.nodes {
+		stats.Edges += len(n.edges)
+		for _, e := range n.edges {
+			stats.Runes += 1 + len(e.label)
+		}
+	}
+	return stats
+}
+
+// Returns the fraction of the nodes of the trie (`nbrNodes`) saved by the minimization,
+// between 0 (nothing shared) and 1
+func (m *MinimizedKeywords) ReductionRatio() float64 {
+	return 1 - float64(len(m.nodes))/float64(m.trieNodes)
+}
+
+// Check if the key `word` exists in the dictionary, see `FlashKeywords.Contains`
+func (m *MinimizedKeywords) Contains(word string) bool {
+	_, ok := m.index(word)
+	return ok
+}
+
+// Returns the corresponding `cleanWord` for the key `word`, see `FlashKeywords.GetKeysWord`
+func (m *MinimizedKeywords) GetKeysWord(word string) (string, error) {
+	index, ok := m.index(word)
+	if !ok {
+		return "", fmt.Errorf("the word %s doesn't exists in the keywords dictionnary", word)
+	}
+	return m.cleanWords[index], nil
+}
+
+// Returns a map of all the keys in the graph with their `cleanWord`
+func (m *MinimizedKeywords) GetAllKeywords() map[string]string {
+	key2Clean := make(map[string]string, len(m.cleanWords))
+	index := 0
+	var key []rune
+	var walk func(node *dawgNode)
+	walk = func(node *dawgNode) {
+		if node.isWord {
+			key2Clean[string(key)] = m.cleanWords[index]
+			index++
+		}
+		for _, e := range node.edges {
+			size := len(key)
+			key = append(append(key, e.char), e.label...)
+			walk(&m.nodes[e.child])
+			key = key[:size]
+		}
+	}
+	walk(&m.nodes[m.root])
+	return key2Clean
+}
+
+// index returns the number of the key `word` in the increasing order of the keys: the
+// keys ending on the way and the ones reached by the edges before the edge taken are smaller
+func (m *MinimizedKeywords) index(word string) (int, bool) {
+	node := &m.nodes[m.root]
+	index := 0
+	for i := 0; i < len(word); {
+		if node.isWord {
+			index++
+		}
+		char, size := utf8.DecodeRuneInString(word[i:])
+		i += size
+		edges := node.edges
+		e := sort.Search(len(edges), func(j int) bool { return edges[j].char >= char })
+		if e == len(edges) || edges[e].char != char {
+			return 0, false
+		}
+		for _, prev := range edges[:e] {
+			index += m.nodes[prev.child].nbrKeys
+		}
+		for _, labelChar := range edges[e].label {
+			char, size = utf8.DecodeRuneInString(word[i:])
+			if i == len(word) || char != labelChar {
+				return 0, false
+			}
+			i += size
+		}
+		node = &m.nodes[edges[e].child]
+	}
+	return index, node.isWord
+}
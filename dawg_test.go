@@ -0,0 +1,97 @@
+package flashtext
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinimizeSharesSuffixes(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	keys2Clean := map[string]string{
+		"manager":     "manager",
+		"managers":    "manager",
+		"management":  "management",
+		"engineer":    "engineer",
+		"engineers":   "engineer",
+		"engineering": "engineering",
+	}
+	for key, clean := range keys2Clean {
+		trie.AddKeyWord(key, clean)
+	}
+	m := trie.Minimize()
+	t.Logf("trie: %+v minimized: %+v ratio: %v", trie.Stats(), m.Stats(), m.ReductionRatio())
+	assert.Equal(t, m.Size(), trie.Size())
+	assert.Equal(t, m.GetAllKeywords(), keys2Clean)
+	// `s` after `manager` and `engineer` and the leaves are shared
+	assert.Equal(t, m.Stats().Nodes < trie.Stats().Nodes, true)
+	assert.Equal(t, m.ReductionRatio() > 0, true)
+	for key, clean := range keys2Clean {
+		assert.Equal(t, m.Contains(key), true)
+		cleanWord, err := m.GetKeysWord(key)
+		assert.Nil(t, err)
+		assert.Equal(t, cleanWord, clean)
+	}
+	for _, key := range []string{"", "man", "managerss", "engine", "engineerin", "Manager"} {
+		assert.Equal(t, m.Contains(key), false, key)
+		_, err := m.GetKeysWord(key)
+		assert.NotNil(t, err)
+	}
+
+	// the minimized keys are a copy
+	trie.Add("managed")
+	assert.Equal(t, m.Contains("managed"), false)
+}
+
+func TestMinimizeSameEndings(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	for _, key := range []string{"ab", "abc", "xb", "xbc"} {
+		trie.Add(key)
+	}
+	m := trie.Minimize()
+	// the root, `b` reached by `a` and `x`, and the leaf `c`
+	assert.Equal(t, m.Stats(), TrieStats{Nodes: 3, Edges: 3, Runes: 5})
+	assert.Equal(t, trie.Stats(), TrieStats{Nodes: 5, Edges: 4, Runes: 6})
+	assert.InDelta(t, m.ReductionRatio(), 0.4, 1e-9)
+
+	empty := NewFlashKeywords(true).Minimize()
+	assert.Equal(t, empty.Size(), 0)
+	assert.Equal(t, empty.Contains(""), false)
+	assert.Equal(t, len(empty.GetAllKeywords()), 0)
+}
+
+func TestMinimizeLikeFlashKeywordsRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(19))
+	randomString := func(size int) string {
+		letters := []rune("abé")
+		word := make([]rune, size)
+		for i := range word {
+			word[i] = letters[r.Intn(len(letters))]
+		}
+		return string(word)
+	}
+	for round := 0; round < 50; round++ {
+		trie := NewFlashKeywords(true)
+		for i := 0; i < 60; i++ {
+			key := randomString(r.Intn(7))
+			if r.Intn(4) == 0 {
+				trie.RemoveKey(key)
+			} else if !trie.Contains(key) {
+				trie.AddKeyWord(key, randomString(r.Intn(2)))
+			}
+		}
+		m := trie.Minimize()
+		assert.Equal(t, m.Size(), trie.Size())
+		assert.Equal(t, m.GetAllKeywords(), trie.GetAllKeywords())
+		for i := 0; i < 50; i++ {
+			key := randomString(r.Intn(7))
+			assert.Equal(t, m.Contains(key), trie.Contains(key), key)
+			cleanWord, err := m.GetKeysWord(key)
+			expectedClean, expectedErr := trie.GetKeysWord(key)
+			assert.Equal(t, cleanWord, expectedClean, key)
+			assert.Equal(t, err, expectedErr, key)
+		}
+		assert.Equal(t, m.Stats().Nodes <= trie.Stats().Nodes, true)
+	}
+}
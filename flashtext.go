@@ -1,22 +1,26 @@
 package flashtext
 
 import (
-	"bufio"
 	"fmt"
 	"log"
-	"os"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 const separator string = "=>"
 
 type TrieNode struct {
 	selfRune  rune
+	label     []rune // runes following `selfRune` on the edge to the node, see radix.go
 	children  map[rune]*TrieNode
 	isWord    bool
 	cleanWord string
 	keep      bool
 	key       string
+	depth     int          // nbr of runes from the root
+	order     int          // insertion order of the key, see `LeftmostFirst`
+	links     []stateLinks // Aho-Corasick links of the states of the edge, see `buildLinks`
 }
 
 func newTrieNode() *TrieNode {
@@ -26,10 +30,17 @@ func newTrieNode() *TrieNode {
 }
 
 type FlashKeywords struct {
-	root          *TrieNode
-	size          int // nbr of keys
-	nbrNodes      int
-	caseSensitive bool
+	root           *TrieNode
+	size           int // nbr of keys
+	nbrNodes       int
+	caseSensitive  bool
+	boundary       bool          // match only whole words
+	wordChars      map[rune]bool // overrides of the default word characters
+	matchKind      MatchKind
+	linked         uint32 // 1 when the Aho-Corasick links are up to date, see `ensureLinks`
+	maxDepth       int    // nbr of runes of the longest key, computed with the links
+	nbrAdded       int    // nbr of keys added so far, gives the insertion order
+	trackPositions bool
 }
 
 // Instantiate a new Instance of the `FlashKeywords` with
@@ -71,28 +82,61 @@ func (tree *FlashKeywords) GetAllKeywords() map[string]string {
 }
 
 func (tree *FlashKeywords) addKeyWord(word string, cleanWord string) {
+	node, overwritten := tree.putKeyWord(word, cleanWord)
+	if overwritten != "" {
+		log.Printf("Warning: overwrite the clean word of %s from %s to %s",
+			node.key, overwritten, cleanWord)
+	}
+}
+
+// putKeyWord is `addKeyWord` without the warning, for the dictionaries loaded without logging.
+// Returns the node of the key and the previous `cleanWord` replaced by `cleanWord`, if any
+func (tree *FlashKeywords) putKeyWord(word string, cleanWord string) (*TrieNode, string) {
 	if !tree.caseSensitive {
+		// the cleanWord keeps its casing, it is written as is by `Replace`
 		word = strings.ToLower(word)
-		cleanWord = strings.ToLower(cleanWord)
 	}
 
+	runes := []rune(word)
 	currentNode := tree.root
-	for _, char := range word {
+	for i := 0; i < len(runes); {
 		if currentNode.isWord {
 			currentNode.keep = true
 		}
 
-		if _, ok := currentNode.children[char]; !ok {
-			currentNode.children[char] = newTrieNode()
+		child, ok := currentNode.children[runes[i]]
+		if !ok {
+			// the rest of the key is the label of a new leaf
+			child = newTrieNode()
+			child.selfRune = runes[i]
+			child.label = runes[i+1:]
+			child.depth = currentNode.depth + len(runes) - i
+			currentNode.children[runes[i]] = child
 			tree.nbrNodes++
+			tree.linked = 0
+			currentNode = child
+			break
+		}
+		i++
+
+		// the key leaves the label of the child or ends inside it
+		n := 0
+		for n < len(child.label) && i+n < len(runes) && child.label[n] == runes[i+n] {
+			n++
 		}
-		currentNode = currentNode.children[char]
-		currentNode.selfRune = char
+		if n < len(child.label) {
+			tree.splitNode(child, n)
+		}
+		i += n
+		currentNode = child
 	}
 
 	if !currentNode.isWord {
 		tree.size++
 		currentNode.isWord = true
+		currentNode.order = tree.nbrAdded
+		tree.nbrAdded++
+		tree.linked = 0
 
 		if len(currentNode.children) != 0 {
 			currentNode.keep = true
@@ -101,12 +145,11 @@ func (tree *FlashKeywords) addKeyWord(word string, cleanWord string) {
 		currentNode.cleanWord = cleanWord
 
 	} else if cleanWord != "" {
-		if currentNode.cleanWord != "" {
-			log.Printf("Warning: overwrite the clean word of %s from %s to %s",
-				word, currentNode.cleanWord, cleanWord)
-		}
+		overwritten := currentNode.cleanWord
 		currentNode.cleanWord = cleanWord
+		return currentNode, overwritten
 	}
+	return currentNode, ""
 }
 
 // Add the key `word` into the trie
@@ -135,43 +178,19 @@ func (tree *FlashKeywords) AddFromMap(keys2synonyms map[string][]string) {
 	}
 }
 
-// Add Multiple Keywords simultaneously from a file by providing the `filePath`
+// Add Multiple Keywords simultaneously from a file by providing the `filePath`,
+// one key per line in the format `key=>cleanWord` or `key`. The malformed lines are
+// skipped, use `AddFromFileWithOptions` to get them or to fail on them.
+// Returns a `*os.PathError` if the file can't be opened or read
 func (tree *FlashKeywords) AddFromFile(filePath string) error {
-	file, err := os.Open(filePath)
-	if err != nil {
-		log.Fatal(err)
-		return err
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		synonym2key := strings.Split(line, separator)
-
-		if len(synonym2key) == 2 {
-			tree.addKeyWord(synonym2key[0], synonym2key[1])
-		} else if len(synonym2key) == 1 {
-			tree.addKeyWord(synonym2key[0], "")
-		} else {
-			log.Printf("Skipped malformed line %s. The Correct format is: key1=>key2", line)
-		}
-	}
-	return nil
+	_, err := tree.AddFromFileWithOptions(filePath, LoadOptions{})
+	return err
 }
 
 // Returns the corresponding `cleanWord` for the key `word` from the trie
 func (tree *FlashKeywords) GetKeysWord(word string) (string, error) {
-	currentNode := tree.root
-
-	for _, char := range word {
-		currentNode = currentNode.children[char]
-		if currentNode == nil {
-			return "", fmt.Errorf("the word %s doesn't exists in the keywords dictionnary", word)
-		}
-	}
-
-	if !currentNode.isWord {
+	currentNode := tree.lookup(word, nil)
+	if currentNode == nil || !currentNode.isWord {
 		return "", fmt.Errorf("the word %s doesn't exists in the keywords dictionnary", word)
 	}
 
@@ -180,54 +199,34 @@ func (tree *FlashKeywords) GetKeysWord(word string) (string, error) {
 
 // Check if the key `word` exists in the trie dictionary
 func (tree *FlashKeywords) Contains(word string) bool {
-	currentNode := tree.root
-
-	for _, char := range word {
-		currentNode = currentNode.children[char]
+	currentNode := tree.lookup(word, nil)
 
-		if currentNode == nil {
-			return false
-		}
-	}
-
-	return currentNode.isWord
+	return currentNode != nil && currentNode.isWord
 }
 
 // Remove the key `word` from the trie dictionary
 func (tree *FlashKeywords) RemoveKey(word string) bool {
-	var nextNode *TrieNode
-	parent := make(map[*TrieNode]*TrieNode)
-
-	currentNode := tree.root
-	for _, currChar := range word {
-		if _, ok := currentNode.children[currChar]; !ok {
-			return false
-		}
-
-		nextNode = currentNode.children[currChar]
-		parent[nextNode] = currentNode
-		currentNode = nextNode
-	}
-
-	if !currentNode.isWord {
+	var path []*TrieNode
+	currentNode := tree.lookup(word, &path)
+	if currentNode == nil || !currentNode.isWord {
 		return false
 	}
 
 	currentNode.isWord = false
 	tree.size--
-	var (
-		parentNode *TrieNode
-		childRune  rune
-	)
+	tree.linked = 0
 	for currentNode != tree.root && len(currentNode.children) == 0 && !currentNode.isWord {
-		parentNode = parent[currentNode]
-		childRune = currentNode.selfRune
-		currentNode = nil
+		path = path[:len(path)-1]
+		parentNode := path[len(path)-1]
 		tree.nbrNodes--
 
-		delete(parentNode.children, childRune)
+		delete(parentNode.children, currentNode.selfRune)
 		currentNode = parentNode
 	}
+	// the node left without key and with a single child is compressed with it
+	if currentNode != tree.root && !currentNode.isWord && len(currentNode.children) == 1 {
+		tree.mergeChild(currentNode)
+	}
 
 	return true
 }
@@ -238,130 +237,132 @@ func (tree *FlashKeywords) RemoveKey(word string) bool {
 //     where A and B are both in the dictionary of the flash keywords
 //   - `CleanWord`: the string with which the found key will be replaced in the text.
 //     We can think of it also like the origin word of the synonym found in the text.
-//   - `Start & End`: span information about the start and end indexes if the key found in the text
+//   - `Start & End`: span information about the start and end indexes if the key found in the text.
+//     The span is a half-open range of byte offsets: `text[Start:End]` is the key found in the text
+//   - `Position`: the span in runes and UTF-16 code units with the line and column of the key,
+//     only filled when enabled with `SetTrackPositions`
 type Result struct {
 	Key       string
 	IsPrefix  bool // support for key the smallest(the prefix) and the longest match
 	CleanWord string
 	Start     int
 	End       int
+	Position  *Position
 }
 
-// Search in the text for the stored keys in the trie and
-// returns a slice of `Result`
-func (tree *FlashKeywords) Search(text string) []Result {
-	if !tree.caseSensitive {
-		text = strings.ToLower(text)
-	}
+// match is a key found in the text by the trie walk or the automaton, `start` and `end`
+// are the byte offsets of the span in the text with `end` exclusive
+type match struct {
+	node     *TrieNode
+	id       uint32 // node of a `flatTrie`, when `node` is nil
+	order    int    // insertion order of the key
+	start    int
+	end      int
+	isPrefix bool
+}
 
-	n := len(text)
-	var res []Result
-	currentNode := tree.root
-	start := 0
+func (m match) result() Result {
+	return Result{
+		Key:       m.node.key,
+		IsPrefix:  m.isPrefix,
+		CleanWord: m.node.cleanWord,
+		Start:     m.start,
+		End:       m.end,
+	}
+}
 
-	for idx, char := range text {
-		currentNode = currentNode.children[char]
+// fold returns the rune used to walk the trie: the lower case of `r` when the trie
+// is case insensitive, consistent with the `strings.ToLower` applied to the keys.
+// The text is never lowered as a whole, so the offsets found are the ones of the original text
+func (tree *FlashKeywords) fold(r rune) rune {
+	return foldRune(r, tree.caseSensitive)
+}
 
-		if currentNode == nil {
-			currentNode = tree.root
-			start = idx + 1
-		} else {
-			if currentNode.isWord {
-				isPrefix := false
-				if currentNode.keep {
-					// possibility to be a prefix of another continous word
-					if idx+1 < n {
-						if _, ok := currentNode.children[rune(text[idx+1])]; ok {
-							isPrefix = true
-						}
-					}
-				}
-				res = append(res, Result{
-					Key:       currentNode.key,
-					IsPrefix:  isPrefix,
-					CleanWord: currentNode.cleanWord,
-					Start:     start,
-					End:       idx,
-				})
-				if !isPrefix {
-					// go back to root with 2 conditions (see TestGoBackToRootTrick):
-					// 	- simple one if keep=false (isPrefix=false by default)
-					// 	- keep can be true but when we look one step ahead
-					// 	  no node is founded => Go back to root
-					currentNode = tree.root
-					start = idx + 1
-				}
-			}
+func foldRune(r rune, caseSensitive bool) rune {
+	if caseSensitive {
+		return r
+	}
+	if r < utf8.RuneSelf {
+		if 'A' <= r && r <= 'Z' {
+			r += 'a' - 'A'
 		}
+		return r
+	}
+	return unicode.ToLower(r)
+}
 
+// Search in the text for the stored keys in the trie and
+// returns a slice of `Result`. The trie must not be updated during the search, but it can
+// be searched concurrently: with a `MatchKind` other than `Greedy`, the first search after
+// an update builds the links of the automaton while the others wait for them
+func (tree *FlashKeywords) Search(text string) []Result {
+	var res []Result
+	tree.matches(text, func(m match) {
+		res = append(res, m.result())
+	})
+	if tree.trackPositions {
+		locate(text, res)
 	}
 	return res
 }
 
 // Replace the keys found in the text with their `cleanWord` if it exists
-// and returns a new string with the replaced keys
+// and returns a new string with the replaced keys. The keys are the ones reported
+// by `Search` for the selected `MatchKind`, the rest of the text is left untouched
+// even when the trie is case insensitive
 func (tree *FlashKeywords) Replace(text string) string {
-	if !tree.caseSensitive {
-		text = strings.ToLower(text)
-	}
+	return tree.ReplaceWithOptions(text, ReplaceOptions{})
+}
 
-	buf := make([]rune, 0, len(text))
-	bufSize := 0
-	currentNode := tree.root
+// the options of `ReplaceWithOptions`:
+//   - `PreserveCase`: transfer the casing of the key found in the text to its `cleanWord`,
+//     with the `cleanWord` "python": "JAVA" => "PYTHON", "Java" => "Python", "java" => "python".
+//     The `cleanWord` is written as it was added when the casing of the key is mixed ("jAvA").
+//     Useful with a case insensitive trie.
+type ReplaceOptions struct {
+	PreserveCase bool
+}
 
-	// track the tail of the buf to know if append or set with a new rune buf[lastChange]
-	// in the case lenght of key is different from lenght of cleanWord
-	lastChange := 0
-	for idx, char := range text {
-		if lastChange < bufSize {
-			buf[lastChange] = char
-			lastChange++
-		} else {
-			buf = append(buf, char)
-			bufSize++
-			lastChange = bufSize
-		}
+// Same as `Replace` with the options `opts`
+func (tree *FlashKeywords) ReplaceWithOptions(text string, opts ReplaceOptions) string {
+	return replaceMatches(text, opts, nodeCleanWord, func(fn func(m match)) {
+		tree.matches(text, fn)
+	})
+}
 
-		currentNode = currentNode.children[char]
-		if currentNode == nil {
-			currentNode = tree.root
-		} else if currentNode.isWord {
-			if currentNode.cleanWord != "" {
-				// repalce opp `leftmost match first`(replace key with the cleanWord)
-				runeKeySize := len([]rune(currentNode.key))
-				start := bufSize - runeKeySize
-				lastChange = start
-				for _, cChar := range currentNode.cleanWord {
-					if start < bufSize {
-						buf[start] = cChar
-						start++
-						lastChange++
-					} else {
-						buf = append(buf, cChar)
-						bufSize++
-						lastChange = bufSize
-						start = bufSize
-					}
-
-				}
-				// done with replacement Go back to root
-				currentNode = tree.root
-
-			} else if currentNode.keep {
-				// in case the currentNode(isWord) doesn't have a cleanWord
-				// worth to check if it is a prefix of another `big word`
-				// to make the replacement opp on the `big word`
-				if _, ok := currentNode.children[rune(text[idx+1])]; !ok {
-					// nothing found go back to root
-					currentNode = tree.root
-				}
-			} else {
-				currentNode = tree.root
-			}
+// nodeCleanWord is the `cleanWord` accessor of the matches of a `FlashKeywords`
+func nodeCleanWord(m match) string {
+	return m.node.cleanWord
+}
 
-		}
+// skipReplace reports whether the key `m` is left untouched by the replacements: the keys
+// without a `cleanWord` and the ones starting before `lastChange`, the end of the last
+// replaced key, are skipped
+func skipReplace(m match, cleanWord string, lastChange int) bool {
+	return cleanWord == "" || m.start < lastChange
+}
 
-	}
+// replaceMatches returns the text with the `cleanWord`, given by `cleanWord`, of the keys
+// given by `matches` to its callback, the rest of the text is left untouched
+func replaceMatches(text string, opts ReplaceOptions, cleanWord func(m match) string, matches func(fn func(m match))) string {
+	var buf strings.Builder
+	buf.Grow(len(text))
+	lastChange := 0
+	matches(func(m match) {
+		clean := cleanWord(m)
+		if skipReplace(m, clean, lastChange) {
+			return
+		}
+		// repalce opp `leftmost match first`(replace key with the cleanWord)
+		buf.WriteString(text[lastChange:m.start])
+		if opts.PreserveCase {
+			buf.WriteString(applyCase(clean, detectCase(text[m.start:m.end])))
+		} else {
+			buf.WriteString(clean)
+		}
+		lastChange = m.end
+	})
+	buf.WriteString(text[lastChange:])
 
-	return string(buf[:lastChange])
+	return buf.String()
 }
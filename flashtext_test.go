@@ -71,7 +71,10 @@ func TestNumberofNodes(t *testing.T) {
 	for _, key := range keys {
 		trie.Add(key)
 	}
-	assert.Equal(t, trie.nbrNodes, 32)
+	// the chains of single child nodes are compressed: the root, `Java`, `J2E`,
+	// `Slice`, `Pizza`, `Chetoos` and `Doritos`
+	assert.Equal(t, trie.nbrNodes, 7)
+	assert.Equal(t, trie.Stats(), TrieStats{Nodes: 7, Edges: 6, Runes: 31})
 }
 
 func TestInsertShortThenLongSearch(t *testing.T) {
@@ -88,7 +91,7 @@ func TestInsertShortThenLongSearch(t *testing.T) {
 	prefixFlag := true
 	for i := 0; i < 2; i++ {
 		assert.Equal(t, res[i].Key, keys[i])
-		assert.Equal(t, text[res[i].Start:res[i].End+1], keys[i])
+		assert.Equal(t, text[res[i].Start:res[i].End], keys[i])
 		assert.Equal(t, res[i].IsPrefix, prefixFlag)
 		t.Logf("Found Key %v, PASS", keys[i])
 		prefixFlag = false // next result is not a prefix of something
@@ -110,7 +113,7 @@ func TestInsertLongThenShortSearch(t *testing.T) {
 	prefixFlag := true
 	for i := 0; i < 2; i++ {
 		assert.Equal(t, res[i].Key, keys[(i+1)%len(keys)])
-		assert.Equal(t, text[res[i].Start:res[i].End+1], keys[(i+1)%len(keys)])
+		assert.Equal(t, text[res[i].Start:res[i].End], keys[(i+1)%len(keys)])
 		assert.Equal(t, res[i].IsPrefix, prefixFlag)
 		t.Logf("Found Key %v, PASS", keys[(i+1)%len(keys)])
 		prefixFlag = false
@@ -210,7 +213,7 @@ func TestSimpleRemoveKeys(t *testing.T) {
 	allKeys := trie.GetAllKeywords()
 	assert.Equal(t, trie.Size(), 1)
 	assert.Equal(t, len(allKeys), 1)
-	assert.Equal(t, trie.nbrNodes, 4)
+	assert.Equal(t, trie.nbrNodes, 2)
 	t.Logf("All keys: %v", allKeys)
 }
 
@@ -221,14 +224,15 @@ func TestOverlapRemoveKeys_1(t *testing.T) {
 		trie.Add(k)
 	}
 	t.Logf("size before deleting key **%v**: %v %v", keys[0], trie.Size(), trie.GetAllKeywords())
-	assert.Equal(t, trie.nbrNodes, len(keys[1])+1)
+	assert.Equal(t, trie.nbrNodes, 3)
 	// deleting the key `cat` will not drop the nodes because `catch`
-	// is still filling the space
+	// is still filling the space, the node(`cat`) is merged with the node(`ch`)
 	trie.RemoveKey(keys[0])
 	allKeys := trie.GetAllKeywords()
 	assert.Equal(t, trie.Size(), 1)
 	assert.Equal(t, len(allKeys), 1)
-	assert.Equal(t, trie.nbrNodes, len(keys[1])+1)
+	assert.Equal(t, trie.nbrNodes, 2)
+	assert.Equal(t, trie.Contains("catch"), true)
 	t.Logf("All keys: %v || nbrNode: %v", allKeys, trie.nbrNodes)
 }
 
@@ -238,15 +242,15 @@ func TestOverlapRemoveKeys_2(t *testing.T) {
 	for _, k := range keys {
 		trie.Add(k)
 	}
-	assert.Equal(t, trie.nbrNodes, len(keys[1])+1)
+	assert.Equal(t, trie.nbrNodes, 3)
 	t.Logf("size before deleting key **%v**: %v %v nbrNode: %v", keys[1], trie.Size(),
 		trie.GetAllKeywords(), trie.nbrNodes)
-	// deleting the key `catch` will drop the node(`h`) and node(`c`)
+	// deleting the key `catch` will drop the node(`ch`)
 	// but will stop because `cat` is still filling the space
 	trie.RemoveKey(keys[1])
 	assert.Equal(t, trie.Size(), 1)
 	assert.Equal(t, len(trie.GetAllKeywords()), 1)
-	assert.Equal(t, trie.nbrNodes, len(keys[0])+1)
+	assert.Equal(t, trie.nbrNodes, 2)
 	t.Logf("Size: %v, allKeys: %v, nbrNode: %v", trie.Size(), trie.GetAllKeywords(), trie.nbrNodes)
 }
 
@@ -256,18 +260,16 @@ func TestOverlapRemoveKeys_3(t *testing.T) {
 	for _, k := range keys {
 		trie.Add(k)
 	}
-	nodes := trie.nbrNodes
+	assert.Equal(t, trie.nbrNodes, 6)
+	// the node left with a single child is merged with it after each removal:
+	// `a` with `b` after removing `af`, `ab` with `c` after removing `abd`
+	nodes := []int{1, 2, 4}
 	for len(keys) != 0 {
 		k := keys[len(keys)-1]
 		t.Logf("%v nbrNode: %v", trie.GetAllKeywords(), trie.nbrNodes)
 		trie.RemoveKey(k)
-		if len(keys) > 1 {
-			nodes--
-		} else {
-			nodes = nodes - 3
-		}
 		keys = keys[:len(keys)-1]
-		assert.Equal(t, trie.nbrNodes, nodes)
+		assert.Equal(t, trie.nbrNodes, nodes[len(keys)])
 		assert.Equal(t, trie.Size(), len(keys))
 		tmp := trie.GetAllKeywords()
 		for _, kk := range keys {
@@ -275,8 +277,8 @@ func TestOverlapRemoveKeys_3(t *testing.T) {
 			assert.Equal(t, ok, true)
 		}
 	}
-	assert.Equal(t, trie.nbrNodes, nodes)
-	t.Logf("%v nbrNode: %v %v", trie.GetAllKeywords(), trie.nbrNodes, nodes)
+	assert.Equal(t, trie.nbrNodes, 1)
+	t.Logf("%v nbrNode: %v", trie.GetAllKeywords(), trie.nbrNodes)
 }
 
 func TestGoBackToRootTrick(t *testing.T) {
@@ -374,19 +376,19 @@ func TestAddWordMultipleTimes(t *testing.T) {
 	cleanWord, err := trie.GetKeysWord(key)
 	assert.Nil(t, err)
 	assert.Equal(t, cleanWord, "")
-	assert.Equal(t, trie.nbrNodes, len(key)+1)
+	assert.Equal(t, trie.nbrNodes, 2)
 	assert.Equal(t, trie.Size(), 1)
 	t.Logf("curr cleanWord: %v", cleanWord)
 
 	trie.addKeyWord("Foo", "Zoo")
-	assert.Equal(t, trie.nbrNodes, len(key)+1)
+	assert.Equal(t, trie.nbrNodes, 2)
 	assert.Equal(t, trie.Size(), 1)
 
 	cleanWord, err = trie.GetKeysWord(key)
 	assert.Nil(t, err)
 	assert.Equal(t, cleanWord, "Zoo")
 	assert.Equal(t, trie.Size(), 1)
-	assert.Equal(t, trie.nbrNodes, len(key)+1)
+	assert.Equal(t, trie.nbrNodes, 2)
 	t.Logf("curr cleanWord: %v", cleanWord)
 
 	trie.addKeyWord("Foo", "Zoo2")
@@ -394,7 +396,7 @@ func TestAddWordMultipleTimes(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, cleanWord, "Zoo2")
 	assert.Equal(t, trie.Size(), 1)
-	assert.Equal(t, trie.nbrNodes, len(key)+1)
+	assert.Equal(t, trie.nbrNodes, 2)
 	t.Logf("curr cleanWord: %v", cleanWord)
 }
 
@@ -465,7 +467,39 @@ func TestRepalceWithFalseCaseSensitive(t *testing.T) {
 	trie.addKeyWord("Foo", "JOJO")
 	text := "HU! foo KIWI"
 	newText := trie.Replace(text)
-	rText := "hu! jojo kiwi"
+	// only the key is replaced, the rest of the text keeps its casing
+	rText := "HU! JOJO KIWI"
+	assert.Equal(t, newText, rText)
+	t.Logf("newText: %v", newText)
+}
+
+func TestReplaceWithFalseCaseSensitiveKeepsText(t *testing.T) {
+	trie := NewFlashKeywords(false)
+	trie.AddKeyWord("Apple", "Fruit")
+	trie.AddKeyWord("FootBall", "Sport")
+	trie.AddKeyWord("🔥", "💪")
+	trie.Add("Banana")
+	text := "I played FOOTBALL, while eating my Apple and a BANANA 🔥"
+	newText := trie.Replace(text)
+	rText := "I played Sport, while eating my Fruit and a BANANA 💪"
 	assert.Equal(t, newText, rText)
 	t.Logf("newText: %v", newText)
 }
+
+func TestReplaceWithFalseCaseSensitiveUnicode(t *testing.T) {
+	// `İ` is 2 bytes long while its lower case `i` is 1 byte long: the spans
+	// must be the ones of the original text
+	trie := NewFlashKeywords(false)
+	trie.AddKeyWord("istanbul", "İSTANBUL")
+	trie.AddKeyWord("ÉTÉ", "summer")
+	text := "İSTANBUL en été, İstanbul"
+	newText := trie.Replace(text)
+	assert.Equal(t, newText, "İSTANBUL en summer, İSTANBUL")
+
+	res := trie.Search(text)
+	assert.Equal(t, len(res), 3)
+	assert.Equal(t, text[res[0].Start:res[0].End], "İSTANBUL")
+	assert.Equal(t, text[res[1].Start:res[1].End], "été")
+	assert.Equal(t, text[res[2].Start:res[2].End], "İstanbul")
+	t.Logf("newText: %v res: %v", newText, res)
+}
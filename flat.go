@@ -0,0 +1,211 @@
+package flashtext
+
+import (
+	"encoding/binary"
+	"unicode/utf8"
+)
+
+// flatTrie finds the keys in place in the tables of a binary dictionary (see `MarshalBinary`),
+// without building the nodes of a `FlashKeywords`. It is read-only and safe for concurrent use.
+// The matching is the `scanner` of `FlashKeywords` walking the node ids of the tables,
+// see `flatWalker`.
+type flatTrie struct {
+	b              *binaryTrie
+	rootASCII      [utf8.RuneSelf]uint32 // children of the root for the ASCII runes
+	stringsTable   string                // the strings of the keys when they can be shared by the results
+	caseSensitive  bool
+	boundary       bool
+	wordChars      map[rune]bool
+	matchKind      MatchKind
+	trackPositions bool
+}
+
+func newFlatTrie(b *binaryTrie) *flatTrie {
+	t := &flatTrie{
+		b:              b,
+		caseSensitive:  b.flags&binaryCaseSensitive != 0,
+		boundary:       b.flags&binaryBoundary != 0,
+		matchKind:      MatchKind(b.matchKind),
+		trackPositions: b.flags&binaryTrackPositions != 0,
+	}
+	if b.nbrWordChars > 0 {
+		t.wordChars = make(map[rune]bool, b.nbrWordChars)
+		for i := uint32(0); i < b.nbrWordChars; i++ {
+			char, isWord := b.wordChar(i)
+			t.wordChars[char] = isWord
+		}
+	}
+	// most of the walks restart from the root which has the most children
+	for char := range t.rootASCII {
+		t.rootASCII[char] = t.searchChild(0, rune(char))
+	}
+	return t
+}
+
+// field returns the field at the byte offset `off` of the node `node`
+func (t *flatTrie) field(node uint32, off uint32) uint32 {
+	return binary.LittleEndian.Uint32(t.b.nodes[node*binaryNodeSize+off:])
+}
+
+func (t *flatTrie) flags(node uint32) uint32   { return t.field(node, 40) }
+func (t *flatTrie) failure(node uint32) uint32 { return t.field(node, 32) }
+func (t *flatTrie) output(node uint32) uint32  { return t.field(node, 36) }
+func (t *flatTrie) depth(node uint32) int      { return int(t.field(node, 28)) }
+
+func (t *flatTrie) isWord(node uint32) bool {
+	return t.flags(node)&binaryIsWord != 0
+}
+
+// child returns the child of `node` reached by `char`, `noNode` if there is none
+func (t *flatTrie) child(node uint32, char rune) uint32 {
+	if node == 0 && 0 <= char && char < utf8.RuneSelf {
+		return t.rootASCII[char]
+	}
+	return t.searchChild(node, char)
+}
+
+// searchChild looks for the child of `node` in its edges sorted by rune: binary search
+func (t *flatTrie) searchChild(node uint32, char rune) uint32 {
+	p := t.b.nodes[node*binaryNodeSize:]
+	lo := binary.LittleEndian.Uint32(p)
+	hi := lo + binary.LittleEndian.Uint32(p[4:])
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		edgeRune := rune(binary.LittleEndian.Uint32(t.b.edges[mid*binaryEdgeSize:]))
+		switch {
+		case edgeRune == char:
+			return binary.LittleEndian.Uint32(t.b.edges[mid*binaryEdgeSize+4:])
+		case edgeRune < char:
+			lo = mid + 1
+		default:
+			hi = mid
+		}
+	}
+	return noNode
+}
+
+// key and cleanWord return the bytes of the strings of the word `node`, they must not be kept
+// after the memory of the tables is released
+func (t *flatTrie) key(node uint32) []byte {
+	off := t.field(node, 8)
+	return t.b.strings[off : off+t.field(node, 12)]
+}
+
+func (t *flatTrie) cleanWord(node uint32) []byte {
+	off := t.field(node, 16)
+	return t.b.strings[off : off+t.field(node, 20)]
+}
+
+func (t *flatTrie) match(node uint32, start int, end int, isPrefix bool) match {
+	return match{id: node, order: int(t.field(node, 24)), start: start, end: end, isPrefix: isPrefix}
+}
+
+// result returns the `Result` of `m`, the strings of the key are copied unless the
+// `stringsTable` is set: the `Result` doesn't depend on the memory of the tables
+func (t *flatTrie) result(m match) Result {
+	res := Result{IsPrefix: m.isPrefix, Start: m.start, End: m.end}
+	if t.stringsTable != "" {
+		off := t.field(m.id, 8)
+		res.Key = t.stringsTable[off : off+t.field(m.id, 12)]
+	} else {
+		res.Key = string(t.key(m.id))
+	}
+	res.CleanWord = t.cleanString(m)
+	return res
+}
+
+// cleanString returns the `cleanWord` of the key `m`, shared with the `stringsTable` when
+// it is set and copied otherwise
+func (t *flatTrie) cleanString(m match) string {
+	if t.stringsTable != "" {
+		off := t.field(m.id, 16)
+		return t.stringsTable[off : off+t.field(m.id, 20)]
+	}
+	return string(t.cleanWord(m.id))
+}
+
+func (t *flatTrie) size() int {
+	return int(t.b.size)
+}
+
+func (t *flatTrie) contains(word string) bool {
+	node := uint32(0)
+	for _, char := range word {
+		if node = t.child(node, char); node == noNode {
+			return false
+		}
+	}
+	return t.isWord(node)
+}
+
+func (t *flatTrie) search(text string) []Result {
+	var res []Result
+	t.matches(text, func(m match) {
+		res = append(res, t.result(m))
+	})
+	if t.trackPositions {
+		locate(text, res)
+	}
+	return res
+}
+
+func (t *flatTrie) replace(text string) string {
+	return replaceMatches(text, ReplaceOptions{}, t.cleanString, func(fn func(m match)) {
+		t.matches(text, fn)
+	})
+}
+
+// matches calls `fn` for every key found in the text with the `MatchKind` of the dictionary
+func (t *flatTrie) matches(text string, fn func(m match)) {
+	s := t.newScanner()
+	s.scan(text, fn)
+}
+
+func (t *flatTrie) newScanner() scanner {
+	var s scanner
+	s.reset(flatWalker{t}, trieState{}, scanSettings{
+		matchKind:     t.matchKind,
+		caseSensitive: t.caseSensitive,
+		boundary:      t.boundary,
+		wordChars:     t.wordChars,
+		maxDepth:      int(t.b.maxDepth),
+	})
+	return s
+}
+
+// flatWalker is the `trieWalker` of a `flatTrie`: the node id of a state is its `pos`,
+// the root is 0
+type flatWalker struct {
+	t *flatTrie
+}
+
+func (w flatWalker) next(s trieState, char rune) (trieState, bool) {
+	child := w.t.child(uint32(s.pos), char)
+	return trieState{pos: int(child)}, child != noNode
+}
+
+func (w flatWalker) isWord(s trieState) bool {
+	return w.t.isWord(uint32(s.pos))
+}
+
+func (w flatWalker) keep(s trieState) bool {
+	return w.t.flags(uint32(s.pos))&binaryKeep != 0
+}
+
+func (w flatWalker) depth(s trieState) int {
+	return w.t.depth(uint32(s.pos))
+}
+
+func (w flatWalker) failure(s trieState) trieState {
+	return trieState{pos: int(w.t.failure(uint32(s.pos)))}
+}
+
+func (w flatWalker) output(s trieState) (trieState, bool) {
+	output := w.t.output(uint32(s.pos))
+	// the root is never a key reported, see `linkState`
+	return trieState{pos: int(output)}, output != noNode && output != 0
+}
+
+func (w flatWalker) match(s trieState, start int, end int, isPrefix bool) match {
+	return w.t.match(uint32(s.pos), start, end, isPrefix)
+}
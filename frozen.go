@@ -0,0 +1,66 @@
+package flashtext
+
+import "fmt"
+
+// FrozenKeywords is an immutable copy of a `FlashKeywords` made by `Freeze`. The nodes are
+// stored in flat arrays, the children of a node being sorted by rune, instead of a map per
+// node: it takes less memory and is faster to walk. It is safe for concurrent use.
+type FrozenKeywords struct {
+	trie *flatTrie
+}
+
+// Freeze returns an immutable copy of the trie with the same keys, settings and results for
+// `Search`, `Replace`, `Contains` and `GetKeysWord`. The trie can still be updated after,
+// the updates are not seen by the `FrozenKeywords`
+func (tree *FlashKeywords) Freeze() (*FrozenKeywords, error) {
+	data, err := tree.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	b, err := parseBinary(data, false)
+	if err != nil {
+		return nil, err
+	}
+	trie := newFlatTrie(b)
+	// the tables are owned by the `FrozenKeywords`: the results share the strings of the keys
+	trie.stringsTable = string(b.strings)
+	return &FrozenKeywords{trie: trie}, nil
+}
+
+// Returns the number of the keys inside the keys dictionary
+func (f *FrozenKeywords) Size() int {
+	return f.trie.size()
+}
+
+// Check if the key `word` exists in the dictionary, see `FlashKeywords.Contains`
+func (f *FrozenKeywords) Contains(word string) bool {
+	return f.trie.contains(word)
+}
+
+// Returns the corresponding `cleanWord` for the key `word`, see `FlashKeywords.GetKeysWord`
+func (f *FrozenKeywords) GetKeysWord(word string) (string, error) {
+	return f.trie.getKeysWord(word)
+}
+
+// Search in the text for the keys of the dictionary, see `FlashKeywords.Search`
+func (f *FrozenKeywords) Search(text string) []Result {
+	return f.trie.search(text)
+}
+
+// Replace the keys found in the text with their `cleanWord`, see `FlashKeywords.Replace`
+func (f *FrozenKeywords) Replace(text string) string {
+	return f.trie.replace(text)
+}
+
+func (t *flatTrie) getKeysWord(word string) (string, error) {
+	node := uint32(0)
+	for _, char := range word {
+		if node = t.child(node, char); node == noNode {
+			return "", fmt.Errorf("the word %s doesn't exists in the keywords dictionnary", word)
+		}
+	}
+	if !t.isWord(node) {
+		return "", fmt.Errorf("the word %s doesn't exists in the keywords dictionnary", word)
+	}
+	return string(t.cleanWord(node)), nil
+}
@@ -0,0 +1,78 @@
+package flashtext
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrozenLikeFlashKeywords(t *testing.T) {
+	texts := []string{
+		streamTestText,
+		"java programing\nlanguage, cat catch atc",
+		"",
+	}
+	for _, kind := range allMatchKinds {
+		for _, boundary := range []bool{false, true} {
+			trie := newStreamTestTrie(kind, boundary)
+			trie.RemoveWordChars("_")
+			frozen, err := trie.Freeze()
+			assert.Nil(t, err)
+
+			assert.Equal(t, frozen.Size(), trie.Size())
+			for _, text := range texts {
+				assert.Equal(t, frozen.Search(text), trie.Search(text), "kind=%v boundary=%v", kind, boundary)
+				assert.Equal(t, frozen.Replace(text), trie.Replace(text), "kind=%v boundary=%v", kind, boundary)
+			}
+			for _, key := range []string{"java", "Java", "java programing", "jav", "北京", "🔥", "atc", "xyz"} {
+				assert.Equal(t, frozen.Contains(key), trie.Contains(key), key)
+				cleanWord, err := frozen.GetKeysWord(key)
+				expectedClean, expectedErr := trie.GetKeysWord(key)
+				assert.Equal(t, cleanWord, expectedClean, key)
+				assert.Equal(t, err, expectedErr, key)
+			}
+		}
+	}
+}
+
+func TestFrozenLikeFlashKeywordsRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	randomString := func(size int) string {
+		// the ASCII runes use the table of the root, `é` the binary search
+		letters := []rune("aAbé. ")
+		word := make([]rune, size)
+		for i := range word {
+			word[i] = letters[r.Intn(len(letters))]
+		}
+		return string(word)
+	}
+	for round := 0; round < 20; round++ {
+		for _, kind := range allMatchKinds {
+			trie := NewFlashKeywords(round%2 == 0)
+			trie.SetMatchKind(kind)
+			trie.SetBoundaryMode(round%4 < 2)
+			for i := 0; i < 20; i++ {
+				trie.AddKeyWord(randomString(1+r.Intn(5)), randomString(r.Intn(3)))
+			}
+			frozen, err := trie.Freeze()
+			assert.Nil(t, err)
+			text := randomString(300)
+			assert.Equal(t, frozen.Search(text), trie.Search(text), "round=%v kind=%v", round, kind)
+			assert.Equal(t, frozen.Replace(text), trie.Replace(text), "round=%v kind=%v", round, kind)
+		}
+	}
+}
+
+func TestFrozenIsImmutable(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.AddKeyWord("java", "python")
+	frozen, err := trie.Freeze()
+	assert.Nil(t, err)
+
+	trie.RemoveKey("java")
+	trie.AddKeyWord("go", "golang")
+	assert.Equal(t, frozen.Replace("java and go"), "python and go")
+	assert.Equal(t, trie.Replace("java and go"), "java and golang")
+	assert.Equal(t, frozen.Size(), 1)
+}
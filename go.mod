@@ -2,7 +2,10 @@ module github.com/ayoyu/flashtext
 
 go 1.17
 
-require github.com/stretchr/testify v1.8.1
+require (
+	github.com/stretchr/testify v1.8.1
+	golang.org/x/text v0.13.0
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
@@ -0,0 +1,226 @@
+package flashtext
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrMalformedLine is matched by `errors.Is` for all the `*MalformedLineError`
+var ErrMalformedLine = errors.New("flashtext: malformed line")
+
+// MalformedLineError is a line of a keywords dictionary which doesn't match its `Format`
+type MalformedLineError struct {
+	Path string // path of the file, empty when read from an `io.Reader`
+	Line int    // line number, starting at 1
+	Text string // content of the line
+}
+
+func (e *MalformedLineError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("line %d: malformed line %q", e.Line, e.Text)
+	}
+	return fmt.Sprintf("%s:%d: malformed line %q", e.Path, e.Line, e.Text)
+}
+
+func (e *MalformedLineError) Unwrap() error {
+	return ErrMalformedLine
+}
+
+// FormatKind is the encoding of a keywords dictionary
+type FormatKind int
+
+const (
+	// Lines is one key per line: `key` or `key` and its `cleanWord` joined by the `Separator`
+	Lines FormatKind = iota
+	// JSON is the format of the python library, the `cleanWord` mapped to its keys like
+	// `AddFromMap`: {"java": ["java_2e", "java programing"]}. The empty keys are skipped
+	JSON
+	// CSV is one key per record with its `cleanWord` in the columns selected by
+	// `KeyColumn` and `CleanColumn`
+	CSV
+)
+
+// Format describes how a keywords dictionary is encoded:
+//   - `Kind`: the encoding of the dictionary.
+//   - `Separator`: the separator between the key and the `cleanWord` of the `Lines`, "=>" if empty.
+//   - `Comma`: the field delimiter of the `CSV` records, ',' if zero.
+//   - `KeyColumn` & `CleanColumn`: the columns of the key and of the `cleanWord` in the `CSV`
+//     records, starting at 0. The keys have no `cleanWord` when `CleanColumn` is negative.
+//   - `Header`: the first `CSV` record is a header and is ignored.
+//   - `Comment`: the `Lines` and `CSV` records starting with this rune are ignored, if not zero.
+//
+// The blank lines are always ignored. The predefined formats can be used as is or as a base.
+type Format struct {
+	Kind        FormatKind
+	Separator   string
+	Comma       rune
+	KeyColumn   int
+	CleanColumn int
+	Header      bool
+	Comment     rune
+}
+
+var (
+	// LineFormat is the format of `AddFromFile`: `key=>cleanWord` or `key`
+	LineFormat = Format{Kind: Lines, Separator: separator}
+	// JSONFormat is the {"cleanWord": ["key1", "key2"]} format of the python library
+	JSONFormat = Format{Kind: JSON}
+	// CSVFormat is the comma separated records `key,cleanWord`
+	CSVFormat = Format{Kind: CSV, Comma: ',', KeyColumn: 0, CleanColumn: 1}
+	// TSVFormat is the tab separated records `key	cleanWord`
+	TSVFormat = Format{Kind: CSV, Comma: '\t', KeyColumn: 0, CleanColumn: 1}
+)
+
+// the options of `AddFromFileWithOptions` and `AddFromReaderWithOptions`:
+//   - `FailOnMalformed`: stop at the first malformed line and return its `*MalformedLineError`.
+//     By default the malformed lines are skipped and returned to the caller.
+type LoadOptions struct {
+	FailOnMalformed bool
+}
+
+// Same as `AddFromFile` with the options `opts`, returns the malformed lines skipped.
+// The errors returned are a `*os.PathError` when the file can't be opened or read, or the
+// `*MalformedLineError` of the first malformed line with `FailOnMalformed`. The keys of the
+// lines before the error are kept in the trie.
+func (tree *FlashKeywords) AddFromFileWithOptions(filePath string, opts LoadOptions) ([]*MalformedLineError, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	skipped, err := tree.AddFromReaderWithOptions(file, LineFormat, opts)
+	for _, lineErr := range skipped {
+		lineErr.Path = filePath
+	}
+	var lineErr *MalformedLineError
+	if errors.As(err, &lineErr) {
+		lineErr.Path = filePath
+	} else if err != nil {
+		err = &os.PathError{Op: "read", Path: filePath, Err: err}
+	}
+	return skipped, err
+}
+
+// Add Multiple Keywords simultaneously from the dictionary read from `r` encoded
+// with the `format`, for example from an `embed.FS` file or an HTTP body.
+// The malformed lines are skipped, use `AddFromReaderWithOptions` to get them or to fail on them
+func (tree *FlashKeywords) AddFromReader(r io.Reader, format Format) error {
+	_, err := tree.AddFromReaderWithOptions(r, format, LoadOptions{})
+	return err
+}
+
+// Same as `AddFromReader` with the options `opts`, returns the malformed lines skipped.
+// The errors returned are the ones of the reader, the decoding errors of the `JSON` and
+// `CSV` formats (`*json.SyntaxError`, `*csv.ParseError`...) or the `*MalformedLineError`
+// of the first malformed line with `FailOnMalformed`. The keys read before the error
+// are kept in the trie. Nothing is logged: a key found again with another `cleanWord`
+// silently takes the last one.
+func (tree *FlashKeywords) AddFromReaderWithOptions(r io.Reader, format Format, opts LoadOptions) ([]*MalformedLineError, error) {
+	switch format.Kind {
+	case Lines:
+		return tree.addFromLines(r, format, opts)
+	case JSON:
+		var clean2Keys map[string][]string
+		if err := json.NewDecoder(r).Decode(&clean2Keys); err != nil {
+			return nil, err
+		}
+		for key, listSynonyms := range clean2Keys {
+			for _, synonym := range listSynonyms {
+				// the JSON has no line to report, the empty keys are skipped
+				if strings.TrimSpace(synonym) != "" {
+					tree.putKeyWord(synonym, key)
+				}
+			}
+		}
+		return nil, nil
+	case CSV:
+		return tree.addFromCSV(r, format, opts)
+	}
+	return nil, fmt.Errorf("flashtext: unknown format kind %d", format.Kind)
+}
+
+// addFromLines adds the keys of the lines `key<Separator>cleanWord` or `key` read from `r`,
+// a line with an empty key is malformed
+func (tree *FlashKeywords) addFromLines(r io.Reader, format Format, opts LoadOptions) ([]*MalformedLineError, error) {
+	sep := format.Separator
+	if sep == "" {
+		sep = separator
+	}
+
+	var skipped []*MalformedLineError
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" ||
+			format.Comment != 0 && strings.HasPrefix(line, string(format.Comment)) {
+			continue
+		}
+
+		synonym2key := strings.Split(line, sep)
+		switch {
+		case len(synonym2key) == 1:
+			tree.putKeyWord(synonym2key[0], "")
+		case len(synonym2key) == 2 && strings.TrimSpace(synonym2key[0]) != "":
+			tree.putKeyWord(synonym2key[0], synonym2key[1])
+		default:
+			lineErr := &MalformedLineError{Line: lineNumber, Text: line}
+			if opts.FailOnMalformed {
+				return skipped, lineErr
+			}
+			skipped = append(skipped, lineErr)
+		}
+	}
+	return skipped, scanner.Err()
+}
+
+// addFromCSV adds the keys of the CSV records read from `r`, a record without
+// the columns of the `format` or with an empty key is malformed
+func (tree *FlashKeywords) addFromCSV(r io.Reader, format Format, opts LoadOptions) ([]*MalformedLineError, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = ','
+	if format.Comma != 0 {
+		reader.Comma = format.Comma
+	}
+	reader.Comment = format.Comment
+	reader.FieldsPerRecord = -1
+
+	var skipped []*MalformedLineError
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return skipped, nil
+		}
+		if err != nil {
+			return skipped, err
+		}
+		if first && format.Header {
+			continue
+		}
+
+		if format.KeyColumn >= 0 && format.KeyColumn < len(record) && format.CleanColumn < len(record) &&
+			strings.TrimSpace(record[format.KeyColumn]) != "" {
+			cleanWord := ""
+			if format.CleanColumn >= 0 {
+				cleanWord = record[format.CleanColumn]
+			}
+			tree.putKeyWord(record[format.KeyColumn], cleanWord)
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		lineErr := &MalformedLineError{Line: line, Text: strings.Join(record, string(reader.Comma))}
+		if opts.FailOnMalformed {
+			return skipped, lineErr
+		}
+		skipped = append(skipped, lineErr)
+	}
+}
@@ -0,0 +1,200 @@
+package flashtext
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddFromFileMissingFile(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	err := trie.AddFromFile("testdata/doesNotExist.txt")
+	var pathErr *os.PathError
+	assert.Equal(t, errors.As(err, &pathErr), true)
+	assert.Equal(t, pathErr.Path, "testdata/doesNotExist.txt")
+	assert.Equal(t, errors.Is(err, os.ErrNotExist), true)
+	assert.Equal(t, trie.Size(), 0)
+}
+
+func TestAddFromFileSkipMalformed(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	skipped, err := trie.AddFromFileWithOptions("testdata/Keys2Synonyms.txt", LoadOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, trie.Size(), 10)
+	assert.Equal(t, len(skipped), 1)
+	assert.Equal(t, skipped[0].Path, "testdata/Keys2Synonyms.txt")
+	assert.Equal(t, skipped[0].Line, 11)
+	assert.Equal(t, skipped[0].Text, "notVlid1=>notValid2=>notValid3")
+	t.Logf("skipped: %v", skipped[0])
+}
+
+func TestAddFromFileFailOnMalformed(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	skipped, err := trie.AddFromFileWithOptions("testdata/Keys2Synonyms.txt", LoadOptions{FailOnMalformed: true})
+	assert.Equal(t, len(skipped), 0)
+	assert.Equal(t, errors.Is(err, ErrMalformedLine), true)
+	var lineErr *MalformedLineError
+	assert.Equal(t, errors.As(err, &lineErr), true)
+	assert.Equal(t, lineErr.Line, 11)
+	assert.Equal(t, err.Error(), `testdata/Keys2Synonyms.txt:11: malformed line "notVlid1=>notValid2=>notValid3"`)
+	// the keys before the malformed line are kept
+	assert.Equal(t, trie.Size(), 10)
+}
+
+func TestAddFromFileBlankLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	err := os.WriteFile(path, []byte("java=>lang\n\n   \npython\na=>b=>c\n"), 0o644)
+	assert.Nil(t, err)
+
+	trie := NewFlashKeywords(true)
+	skipped, err := trie.AddFromFileWithOptions(path, LoadOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, trie.Size(), 2)
+	assert.Equal(t, trie.Contains(""), false)
+	assert.Equal(t, len(skipped), 1)
+	assert.Equal(t, skipped[0].Line, 5)
+}
+
+func TestAddFromReaderFormats(t *testing.T) {
+	customLines := LineFormat
+	customLines.Separator = " -> "
+	customLines.Comment = '#'
+	csvColumns := CSVFormat
+	csvColumns.Header = true
+	csvColumns.KeyColumn = 1
+	csvColumns.CleanColumn = 0
+	keysOnly := TSVFormat
+	keysOnly.CleanColumn = -1
+
+	testdata := []struct {
+		name     string
+		format   Format
+		input    string
+		expected map[string]string
+	}{
+		{
+			name:     "Lines",
+			format:   LineFormat,
+			input:    "java_2e=>java\nBanana\n\n#tag=>hash\n",
+			expected: map[string]string{"java_2e": "java", "Banana": "", "#tag": "hash"},
+		},
+		{
+			name:     "CustomSeparatorAndComments",
+			format:   customLines,
+			input:    "# the languages\njava_2e -> java\n  \nBanana\n",
+			expected: map[string]string{"java_2e": "java", "Banana": ""},
+		},
+		{
+			name:     "JSON",
+			format:   JSONFormat,
+			input:    `{"java": ["java_2e", "java programing"], "product management": ["PM"]}`,
+			expected: map[string]string{"java_2e": "java", "java programing": "java", "PM": "product management"},
+		},
+		{
+			name:     "CSV",
+			format:   CSVFormat,
+			input:    "java_2e,java\n\"java, programing\",java\nBanana,\n",
+			expected: map[string]string{"java_2e": "java", "java, programing": "java", "Banana": ""},
+		},
+		{
+			name:     "CSVColumnsWithHeader",
+			format:   csvColumns,
+			input:    "clean,key,comment\njava,java_2e,first\npython,pypy,second\n",
+			expected: map[string]string{"java_2e": "java", "pypy": "python"},
+		},
+		{
+			name:     "TSVKeysOnly",
+			format:   keysOnly,
+			input:    "java_2e\tignored\nBanana\n",
+			expected: map[string]string{"java_2e": "", "Banana": ""},
+		},
+	}
+	for _, item := range testdata {
+		trie := NewFlashKeywords(true)
+		err := trie.AddFromReader(strings.NewReader(item.input), item.format)
+		assert.Nil(t, err, item.name)
+		assert.Equal(t, trie.GetAllKeywords(), item.expected, item.name)
+	}
+}
+
+func TestAddFromReaderMalformed(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	skipped, err := trie.AddFromReaderWithOptions(strings.NewReader("a,b\nc\n,d\ne,f\n"), CSVFormat, LoadOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, trie.GetAllKeywords(), map[string]string{"a": "b", "e": "f"})
+	assert.Equal(t, len(skipped), 2)
+	assert.Equal(t, skipped[0].Line, 2)
+	assert.Equal(t, skipped[1].Line, 3)
+	assert.Equal(t, skipped[1].Text, ",d")
+	assert.Equal(t, skipped[1].Error(), `line 3: malformed line ",d"`)
+
+	_, err = trie.AddFromReaderWithOptions(strings.NewReader("a=>b=>c"), LineFormat, LoadOptions{FailOnMalformed: true})
+	assert.Equal(t, errors.Is(err, ErrMalformedLine), true)
+
+	var syntaxErr *json.SyntaxError
+	err = trie.AddFromReader(strings.NewReader(`{"java": ["java_2e"`), JSONFormat)
+	assert.NotNil(t, err)
+	err = trie.AddFromReader(strings.NewReader(`{"java": ["java_2e"],}`), JSONFormat)
+	assert.Equal(t, errors.As(err, &syntaxErr), true)
+
+	var parseErr *csv.ParseError
+	err = trie.AddFromReader(strings.NewReader("a,\"b\n"), CSVFormat)
+	assert.Equal(t, errors.As(err, &parseErr), true)
+}
+
+func TestAddFromReaderDoesNotLog(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	for _, c := range []struct {
+		format Format
+		dict   string
+	}{
+		{LineFormat, "java=>Java\njava=>Java 8\n"},
+		{CSVFormat, "java,Java\njava,Java 8\n"},
+		{JSONFormat, `{"Java": ["java"], "Java 8": ["java", "jdk"]}`},
+	} {
+		trie := NewFlashKeywords(false)
+		err := trie.AddFromReader(strings.NewReader(c.dict), c.format)
+		assert.Nil(t, err)
+		assert.Equal(t, trie.Contains("java"), true)
+	}
+	trie := NewFlashKeywords(false)
+	err := trie.AddFromReader(strings.NewReader("java=>Java\njava=>Java 8\n"), LineFormat)
+	assert.Nil(t, err)
+	cleanWord, _ := trie.GetKeysWord("java")
+	assert.Equal(t, cleanWord, "Java 8")
+	assert.Equal(t, logs.String(), "")
+
+	// the keys added one by one are still warned about
+	trie.AddKeyWord("java", "Java 11")
+	assert.Equal(t, strings.Contains(logs.String(), "overwrite the clean word of java from Java 8 to Java 11"), true)
+}
+
+func TestAddFromReaderEmptyKeys(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	skipped, err := trie.AddFromReaderWithOptions(strings.NewReader("=>clean\njava=>Java\n  =>x\n"), LineFormat, LoadOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, len(skipped), 2)
+	assert.Equal(t, skipped[0].Line, 1)
+	assert.Equal(t, skipped[1].Line, 3)
+	assert.Equal(t, trie.GetAllKeywords(), map[string]string{"java": "Java"})
+
+	_, err = trie.AddFromReaderWithOptions(strings.NewReader("=>clean"), LineFormat, LoadOptions{FailOnMalformed: true})
+	assert.Equal(t, errors.Is(err, ErrMalformedLine), true)
+
+	trie = NewFlashKeywords(true)
+	err = trie.AddFromReader(strings.NewReader(`{"x": [""], "Go": ["golang", " "]}`), JSONFormat)
+	assert.Nil(t, err)
+	assert.Equal(t, trie.GetAllKeywords(), map[string]string{"golang": "Go"})
+	assert.Equal(t, trie.Size(), 1)
+}
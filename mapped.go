@@ -0,0 +1,108 @@
+package flashtext
+
+import "os"
+
+// MappedKeywords is a read-only dictionary whose tables are read in place from a binary
+// dictionary file written with `MarshalBinary`: the file is memory-mapped, so its pages
+// are loaded on demand and shared between the processes opening the same file.
+// `Search`, `Replace`, `Contains` and `GetKeysWord` give the same results as the `FlashKeywords` encoded
+// in the file, with its settings (`MatchKind`, boundary mode...). It is safe for concurrent
+// use until `Close` is called.
+type MappedKeywords struct {
+	trie *flatTrie
+	data []byte
+}
+
+// the options of `OpenMappedWithOptions`:
+//   - `Unchecked`: only the header of the file is checked when opened, in constant time,
+//     instead of the checksum and the tables read in full. For the trusted files, a corrupted
+//     file can then give wrong results, panic or loop in the searches. `Check` does the
+//     checks skipped later on.
+type MappedOptions struct {
+	Unchecked bool
+}
+
+// Open the binary dictionary `path` written with `MarshalBinary` as a `MappedKeywords`.
+// The file is checked when opened like `UnmarshalBinary` does, returning an error matching
+// `ErrBinaryFormat`, `ErrBinaryVersion` or `ErrBinaryCorrupted`: the whole file is read,
+// see `MappedOptions.Unchecked` to skip it. The file must not be modified while it is opened,
+// replace it with a rename instead. On the platforms without `mmap` the file is read in memory.
+func OpenMapped(path string) (*MappedKeywords, error) {
+	return OpenMappedWithOptions(path, MappedOptions{})
+}
+
+// Same as `OpenMapped` with the options `opts`
+func OpenMappedWithOptions(path string, opts MappedOptions) (*MappedKeywords, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() < binaryHeaderSize+4 || int64(int(info.Size())) != info.Size() {
+		return nil, ErrBinaryFormat
+	}
+	data, err := mapFile(file, int(info.Size()))
+	if err != nil {
+		return nil, &os.PathError{Op: "mmap", Path: path, Err: err}
+	}
+
+	parse := parseBinary
+	if opts.Unchecked {
+		parse = parseHeader
+	}
+	b, err := parse(data, !opts.Unchecked)
+	if err != nil {
+		unmapFile(data)
+		return nil, err
+	}
+	return &MappedKeywords{trie: newFlatTrie(b), data: data}, nil
+}
+
+// Check verifies the checksum and the tables of the file like `OpenMapped` does, for the
+// dictionaries opened with `MappedOptions.Unchecked`. Returns an error matching `ErrBinaryCorrupted`
+func (m *MappedKeywords) Check() error {
+	_, err := parseBinary(m.data, true)
+	return err
+}
+
+// Release the mapped memory of the dictionary, it must not be used anymore.
+// The `Result`s returned before don't depend on the mapped memory
+func (m *MappedKeywords) Close() error {
+	if m.data == nil {
+		return nil
+	}
+	data := m.data
+	m.data = nil
+	m.trie = nil
+	return unmapFile(data)
+}
+
+// Returns the number of the keys inside the keys dictionary
+func (m *MappedKeywords) Size() int {
+	return m.trie.size()
+}
+
+// Check if the key `word` exists in the dictionary, see `FlashKeywords.Contains`
+func (m *MappedKeywords) Contains(word string) bool {
+	return m.trie.contains(word)
+}
+
+// Returns the corresponding `cleanWord` for the key `word`, see `FlashKeywords.GetKeysWord`
+func (m *MappedKeywords) GetKeysWord(word string) (string, error) {
+	return m.trie.getKeysWord(word)
+}
+
+// Search in the text for the keys of the dictionary, see `FlashKeywords.Search`
+func (m *MappedKeywords) Search(text string) []Result {
+	return m.trie.search(text)
+}
+
+// Replace the keys found in the text with their `cleanWord`, see `FlashKeywords.Replace`
+func (m *MappedKeywords) Replace(text string) string {
+	return m.trie.replace(text)
+}
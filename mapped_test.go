@@ -0,0 +1,160 @@
+package flashtext
+
+import (
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeMapped writes the binary dictionary of `trie` in a temporary file and opens it
+func writeMapped(t *testing.T, trie *FlashKeywords) *MappedKeywords {
+	data, err := trie.MarshalBinary()
+	assert.Nil(t, err)
+	path := filepath.Join(t.TempDir(), "keys.bin")
+	assert.Nil(t, os.WriteFile(path, data, 0o644))
+	mapped, err := OpenMapped(path)
+	assert.Nil(t, err)
+	return mapped
+}
+
+func TestMappedLikeFlashKeywords(t *testing.T) {
+	texts := []string{
+		streamTestText,
+		"call chetoos055-5647-3456 chetoosPiza",
+		"JAVA programing\nlanguage, c++ c+++ java_programing",
+		"",
+	}
+	for _, kind := range allMatchKinds {
+		for _, boundary := range []bool{false, true} {
+			trie := newStreamTestTrie(kind, boundary)
+			trie.AddWordChars("+")
+			trie.AddKeyWord("c++", "cpp")
+			trie.Add("chetoos")
+			trie.Add("chetoosPiza")
+			trie.SetTrackPositions(true)
+			mapped := writeMapped(t, trie)
+
+			assert.Equal(t, mapped.Size(), trie.Size())
+			for _, text := range texts {
+				assert.Equal(t, mapped.Search(text), trie.Search(text), "kind=%v boundary=%v", kind, boundary)
+				assert.Equal(t, mapped.Replace(text), trie.Replace(text), "kind=%v boundary=%v", kind, boundary)
+			}
+			for _, key := range []string{"java", "Java", "java programing", "jav", "北京", "c++", "chetoospiza", ""} {
+				assert.Equal(t, mapped.Contains(key), trie.Contains(key), key)
+			}
+			assert.Nil(t, mapped.Close())
+		}
+	}
+}
+
+func TestMappedLikeFlashKeywordsRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	randomString := func(size int) string {
+		letters := []rune("abcAé ")
+		word := make([]rune, size)
+		for i := range word {
+			word[i] = letters[r.Intn(len(letters))]
+		}
+		return string(word)
+	}
+	for round := 0; round < 20; round++ {
+		for _, kind := range allMatchKinds {
+			trie := NewFlashKeywords(round%2 == 0)
+			trie.SetMatchKind(kind)
+			trie.SetBoundaryMode(round%4 < 2)
+			for i := 0; i < 15; i++ {
+				trie.AddKeyWord(randomString(1+r.Intn(4)), randomString(r.Intn(3)))
+			}
+			mapped := writeMapped(t, trie)
+			text := randomString(200)
+			assert.Equal(t, mapped.Search(text), trie.Search(text), "round=%v kind=%v", round, kind)
+			assert.Equal(t, mapped.Replace(text), trie.Replace(text), "round=%v kind=%v", round, kind)
+			assert.Nil(t, mapped.Close())
+		}
+	}
+}
+
+func TestMappedResultsAfterClose(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.AddKeyWord("java", "python")
+	mapped := writeMapped(t, trie)
+	res := mapped.Search("I like java")
+	assert.Nil(t, mapped.Close())
+	assert.Nil(t, mapped.Close())
+	assert.Equal(t, res[0].Key, "java")
+	assert.Equal(t, res[0].CleanWord, "python")
+}
+
+func TestMappedConcurrentSearch(t *testing.T) {
+	mapped := writeMapped(t, newStreamTestTrie(LeftmostLongest, true))
+	defer mapped.Close()
+	expected := mapped.Replace(streamTestText)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				assert.Equal(t, mapped.Replace(streamTestText), expected)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestOpenMappedErrors(t *testing.T) {
+	_, err := OpenMapped(filepath.Join(t.TempDir(), "missing.bin"))
+	assert.Equal(t, errors.Is(err, os.ErrNotExist), true)
+
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	assert.Nil(t, os.WriteFile(path, []byte("java=>python\n"), 0o644))
+	_, err = OpenMapped(path)
+	assert.Equal(t, errors.Is(err, ErrBinaryFormat), true)
+
+	trie := NewFlashKeywords(true)
+	trie.AddKeyWord("java", "python")
+	data, err := trie.MarshalBinary()
+	assert.Nil(t, err)
+	data[binaryHeaderSize] ^= 1
+	assert.Nil(t, os.WriteFile(path, data, 0o644))
+	_, err = OpenMapped(path)
+	assert.Equal(t, errors.Is(err, ErrBinaryCorrupted), true)
+}
+
+func TestOpenMappedUnchecked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.bin")
+	for _, kind := range allMatchKinds {
+		trie := newStreamTestTrie(kind, true)
+		data, err := trie.MarshalBinary()
+		assert.Nil(t, err)
+		assert.Nil(t, os.WriteFile(path, data, 0o644))
+		mapped, err := OpenMappedWithOptions(path, MappedOptions{Unchecked: true})
+		assert.Nil(t, err)
+		assert.Nil(t, mapped.Check())
+		assert.Equal(t, mapped.Search(streamTestText), trie.Search(streamTestText), "kind=%v", kind)
+		assert.Equal(t, mapped.Replace(streamTestText), trie.Replace(streamTestText), "kind=%v", kind)
+		assert.Nil(t, mapped.Close())
+	}
+
+	// the corrupted tables are found by `Check`, the header is still checked when opened
+	trie := NewFlashKeywords(true)
+	trie.AddKeyWord("java", "python")
+	data, err := trie.MarshalBinary()
+	assert.Nil(t, err)
+	data[binaryHeaderSize] ^= 1
+	assert.Nil(t, os.WriteFile(path, data, 0o644))
+	mapped, err := OpenMappedWithOptions(path, MappedOptions{Unchecked: true})
+	assert.Nil(t, err)
+	assert.Equal(t, errors.Is(mapped.Check(), ErrBinaryCorrupted), true)
+	assert.Nil(t, mapped.Close())
+
+	data[4]++
+	assert.Nil(t, os.WriteFile(path, data, 0o644))
+	_, err = OpenMappedWithOptions(path, MappedOptions{Unchecked: true})
+	assert.Equal(t, errors.Is(err, ErrBinaryVersion), true)
+}
@@ -0,0 +1,129 @@
+package flashtext
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Matcher holds a dictionary reloaded as a whole from its files while it is searched: a new
+// `FlashKeywords` is built aside and swapped in atomically, so the searches never wait for
+// a reload. The searches started before the swap finish on the previous dictionary and the
+// next ones use the new one. A failed reload leaves the previous dictionary in place.
+// It is safe for concurrent use.
+type Matcher struct {
+	current atomic.Value // *FlashKeywords, never updated once stored
+	status  atomic.Value // reloadStatus
+	mu      sync.Mutex   // one reload at a time
+
+	newTree func() *FlashKeywords
+	paths   []string
+}
+
+// reloadStatus is the outcome of the last reload
+type reloadStatus struct {
+	loadedAt time.Time // last successful reload
+	err      error
+}
+
+// NewMatcher returns a `Matcher` with the keys of the files `paths` loaded by `AddFromFile`.
+// `newTree` returns the empty trie filled at each reload, with its settings (case sensitivity,
+// `MatchKind`, boundary mode...). Returns the error of the first load
+func NewMatcher(newTree func() *FlashKeywords, paths ...string) (*Matcher, error) {
+	m := &Matcher{
+		newTree: newTree,
+		paths:   append([]string(nil), paths...),
+	}
+	if err := m.Reload(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Reload builds a new dictionary from the files and swaps it in, the searches go on with
+// the previous dictionary in the meantime. Returns the error of `AddFromFile`, the previous
+// dictionary is then kept
+func (m *Matcher) Reload() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status, _ := m.status.Load().(reloadStatus)
+	tree := m.newTree()
+	for _, path := range m.paths {
+		if err := tree.AddFromFile(path); err != nil {
+			status.err = err
+			m.status.Store(status)
+			return err
+		}
+	}
+	m.store(tree)
+	m.status.Store(reloadStatus{loadedAt: time.Now()})
+	return nil
+}
+
+// LoadedAt returns the time of the last successful load of the files
+func (m *Matcher) LoadedAt() time.Time {
+	status, _ := m.status.Load().(reloadStatus)
+	return status.loadedAt
+}
+
+// LastError returns the error of the last reload, nil if it succeeded
+func (m *Matcher) LastError() error {
+	status, _ := m.status.Load().(reloadStatus)
+	return status.err
+}
+
+// Swap replaces the dictionary by `tree`, which must not be updated anymore
+func (m *Matcher) Swap(tree *FlashKeywords) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.store(tree)
+}
+
+// store makes `tree` the current dictionary: the links of the automaton are built before,
+// so the concurrent searches only read the trie
+func (m *Matcher) store(tree *FlashKeywords) {
+	tree.ensureLinks()
+	m.current.Store(tree)
+}
+
+// Keywords returns the current dictionary, it is kept as is by the next reloads.
+// It can be searched concurrently but must not be updated
+func (m *Matcher) Keywords() *FlashKeywords {
+	return m.current.Load().(*FlashKeywords)
+}
+
+// Paths returns the files of the dictionary
+func (m *Matcher) Paths() []string {
+	return append([]string(nil), m.paths...)
+}
+
+// Returns the number of the keys inside the current dictionary
+func (m *Matcher) Size() int {
+	return m.Keywords().Size()
+}
+
+// Check if the key `word` exists in the current dictionary
+func (m *Matcher) Contains(word string) bool {
+	return m.Keywords().Contains(word)
+}
+
+// Returns the corresponding `cleanWord` for the key `word` from the current dictionary
+func (m *Matcher) GetKeysWord(word string) (string, error) {
+	return m.Keywords().GetKeysWord(word)
+}
+
+// Search in the text for the keys of the current dictionary, see `FlashKeywords.Search`
+func (m *Matcher) Search(text string) []Result {
+	return m.Keywords().Search(text)
+}
+
+// Replace the keys found in the text with their `cleanWord`, see `FlashKeywords.Replace`
+func (m *Matcher) Replace(text string) string {
+	return m.Keywords().Replace(text)
+}
+
+// Same as `Replace` with the options `opts`, see `FlashKeywords.ReplaceWithOptions`
+func (m *Matcher) ReplaceWithOptions(text string, opts ReplaceOptions) string {
+	return m.Keywords().ReplaceWithOptions(text, opts)
+}
@@ -0,0 +1,87 @@
+package flashtext
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newMatcherTestTree() *FlashKeywords {
+	tree := NewFlashKeywords(false)
+	tree.SetMatchKind(LeftmostLongest)
+	return tree
+}
+
+func TestMatcherReload(t *testing.T) {
+	dir := t.TempDir()
+	langs := filepath.Join(dir, "langs.txt")
+	skills := filepath.Join(dir, "skills.txt")
+	assert.Nil(t, os.WriteFile(langs, []byte("java=>lang\ngolang=>go\n"), 0o644))
+	assert.Nil(t, os.WriteFile(skills, []byte("java programing=>skill\n"), 0o644))
+
+	m, err := NewMatcher(newMatcherTestTree, langs, skills)
+	assert.Nil(t, err)
+	assert.Equal(t, m.Paths(), []string{langs, skills})
+	assert.Equal(t, m.Size(), 3)
+	text := "Java programing and java in golang"
+	assert.Equal(t, m.Replace(text), "skill and lang in go")
+	old := m.Keywords()
+
+	assert.Nil(t, os.WriteFile(langs, []byte("java=>JVM\n"), 0o644))
+	assert.Nil(t, m.Reload())
+	assert.Equal(t, m.Size(), 2)
+	assert.Equal(t, m.Contains("golang"), false)
+	assert.Equal(t, m.Replace(text), "skill and JVM in golang")
+	cleanWord, err := m.GetKeysWord("java")
+	assert.Nil(t, err)
+	assert.Equal(t, cleanWord, "JVM")
+	// the previous dictionary is left as is
+	assert.Equal(t, old.Replace(text), "skill and lang in go")
+
+	// a failed reload keeps the current dictionary
+	assert.Nil(t, os.Remove(skills))
+	err = m.Reload()
+	var pathErr *os.PathError
+	assert.Equal(t, errors.As(err, &pathErr), true)
+	assert.Equal(t, m.Size(), 2)
+	assert.Equal(t, m.ReplaceWithOptions("JAVA", ReplaceOptions{PreserveCase: true}), "JVM")
+
+	m.Swap(NewFlashKeywords(true))
+	assert.Equal(t, m.Size(), 0)
+
+	_, err = NewMatcher(newMatcherTestTree, skills)
+	assert.NotNil(t, err)
+}
+
+func TestMatcherConcurrentReload(t *testing.T) {
+	// run with `go test -race`: the searches go on during the reloads
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	assert.Nil(t, os.WriteFile(path, []byte("cat=>A\ncatch=>B\n"), 0o644))
+	m, err := NewMatcher(newMatcherTestTree, path)
+	assert.Nil(t, err)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			assert.Nil(t, m.Reload())
+		}
+	}()
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				if newText := m.Replace("cat catch"); newText != "A B" {
+					t.Errorf("invalid replace %q", newText)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,20 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package flashtext
+
+import (
+	"io"
+	"os"
+)
+
+// mapFile reads the `size` first bytes of `file` in memory, `mmap` is not available
+func mapFile(file *os.File, size int) ([]byte, error) {
+	data := make([]byte, size)
+	_, err := io.ReadFull(file, data)
+	return data, err
+}
+
+func unmapFile(data []byte) error {
+	return nil
+}
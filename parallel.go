@@ -0,0 +1,164 @@
+package flashtext
+
+import (
+	"runtime"
+	"sync"
+	"unicode/utf8"
+)
+
+// parallelMinChunk is the smallest part of the text searched by a goroutine of `SearchParallel`
+const parallelMinChunk = 16 << 10
+
+// SearchParallel is the same as `Search` with the text split in `workers` parts searched
+// concurrently, `runtime.GOMAXPROCS(0)` parts if `workers` is not positive. The results are
+// exactly the ones of `Search`, in the same order. Useful for the texts of several megabytes:
+// the texts too small to be split are searched by `Search`. Like `Search`, it must not be
+// called while the trie is updated
+func (tree *FlashKeywords) SearchParallel(text string, workers int) []Result {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(text)/parallelMinChunk {
+		workers = len(text) / parallelMinChunk
+	}
+	if workers <= 1 {
+		return tree.Search(text)
+	}
+	// the scanners of the goroutines only read the trie, `Greedy` doesn't need the links
+	if tree.matchKind != Greedy {
+		tree.ensureLinks()
+	}
+	maxDepth := tree.longestKey()
+
+	chunks := make([]chunkScan, workers)
+	start := 0
+	for i := range chunks {
+		end := len(text)
+		if i < workers-1 {
+			end = (i + 1) * len(text) / workers
+			for end < len(text) && !utf8.RuneStart(text[end]) {
+				end++
+			}
+		}
+		chunks[i].start, chunks[i].end = start, end
+		start = end
+	}
+	var wg sync.WaitGroup
+	for i := range chunks {
+		wg.Add(1)
+		go func(c *chunkScan) {
+			defer wg.Done()
+			c.scan(tree, text, maxDepth)
+		}(&chunks[i])
+	}
+	wg.Wait()
+
+	// the first part is searched like by `Search`, the scanner of the parts already merged
+	// goes on over the next part until both scanners are clean after the same rune: the
+	// next part is then searched like by `Search` from there
+	var res []Result
+	collect := func(m match) {
+		res = append(res, m.result())
+	}
+	for _, m := range chunks[0].matches {
+		collect(m)
+	}
+	s := chunks[0].scanner
+	for _, c := range chunks[1:] {
+		k := 0
+		for idx := c.start; idx < c.end; {
+			char, size := utf8.DecodeRuneInString(text[idx:])
+			s.step(char, idx, size, collect)
+			idx += size
+			for k < len(c.syncs) && c.syncs[k].offset < idx {
+				k++
+			}
+			if k < len(c.syncs) && c.syncs[k].offset == idx && s.clean() {
+				for _, m := range c.matches[c.syncs[k].nbrMatches:] {
+					collect(m)
+				}
+				s = c.scanner
+				break
+			}
+		}
+	}
+	s.finish(len(text), collect)
+	if tree.trackPositions {
+		locate(text, res)
+	}
+	return res
+}
+
+// chunkScan is a part of the text searched by a goroutine of `SearchParallel` with a new
+// scanner, as if the text started there
+type chunkScan struct {
+	start, end int
+	scanner    scanner // left unfinished at `end`
+	matches    []match
+	syncs      []chunkSync // the first offsets where the scanner is clean
+}
+
+type chunkSync struct {
+	offset     int
+	nbrMatches int // nbr of matches reported before `offset`
+}
+
+// scan searches the part of the text, `maxDepth` is the nbr of runes of the longest key
+func (c *chunkScan) scan(tree *FlashKeywords, text string, maxDepth int) {
+	collect := func(m match) {
+		c.matches = append(c.matches, m)
+	}
+	c.scanner = tree.newScanner()
+	if c.start > 0 && tree.boundary {
+		prev, _ := utf8.DecodeLastRuneInString(text[:c.start])
+		c.scanner.prevWord = tree.isWordRune(prev)
+	}
+	// the scanner of the previous part is usually clean again within the length
+	// of the longest key, the part is searched again by it otherwise
+	window := 4*maxDepth + 64
+	for idx := c.start; idx < c.end; {
+		char, size := utf8.DecodeRuneInString(text[idx:])
+		c.scanner.step(char, idx, size, collect)
+		idx += size
+		if window > 0 {
+			window--
+			if c.scanner.clean() {
+				c.syncs = append(c.syncs, chunkSync{offset: idx, nbrMatches: len(c.matches)})
+			}
+		}
+	}
+}
+
+// longestKey returns the nbr of runes of the longest key, like `maxDepth` but without
+// writing to the trie when the links are out of date
+func (tree *FlashKeywords) longestKey() int {
+	if tree.isLinked() {
+		return tree.maxDepth
+	}
+	longest := 0
+	stack := []*TrieNode{tree.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if node.isWord && node.depth > longest {
+			longest = node.depth
+		}
+		for _, child := range node.children {
+			stack = append(stack, child)
+		}
+	}
+	return longest
+}
+
+// clean reports whether the scanner keeps nothing from the runes given so far but whether the
+// last one is a word rune: it behaves from now on like a new scanner, no key found later can
+// start before the next rune
+func (s *scanner) clean() bool {
+	if s.state != s.root {
+		return false
+	}
+	if s.matchKind == Greedy {
+		return !s.hasWord
+	}
+	return len(s.ends) == 0 && len(s.resolver.pending) == 0
+}
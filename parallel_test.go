@@ -0,0 +1,86 @@
+package flashtext
+
+import (
+	"math/rand"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchParallelLikeSearch(t *testing.T) {
+	text := strings.Repeat(streamTestText+"\n", 1000)
+	for _, kind := range allMatchKinds {
+		for _, boundary := range []bool{false, true} {
+			trie := newStreamTestTrie(kind, boundary)
+			trie.SetTrackPositions(true)
+			expected := trie.Search(text)
+			for _, workers := range []int{0, 1, 3, 8} {
+				res := trie.SearchParallel(text, workers)
+				assert.Equal(t, res, expected, "kind=%v boundary=%v workers=%v", kind, boundary, workers)
+			}
+		}
+	}
+	// too small to be split
+	trie := newStreamTestTrie(Greedy, false)
+	assert.Equal(t, trie.SearchParallel(streamTestText, 4), trie.Search(streamTestText))
+	assert.Equal(t, len(trie.SearchParallel("", 4)), 0)
+}
+
+func TestSearchParallelRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(23))
+	randomString := func(letters []rune, size int) string {
+		word := make([]rune, size)
+		for i := range word {
+			word[i] = letters[r.Intn(len(letters))]
+		}
+		return string(word)
+	}
+	for round := 0; round < 6; round++ {
+		for _, kind := range allMatchKinds {
+			trie := NewFlashKeywords(true)
+			trie.SetMatchKind(kind)
+			trie.SetBoundaryMode(round%2 == 0)
+			for i := 0; i < 20; i++ {
+				trie.Add(randomString([]rune("abé "), 1+r.Intn(6)))
+			}
+			text := randomString([]rune("abé c"), 40000)
+			assert.Equal(t, trie.SearchParallel(text, 1+r.Intn(8)), trie.Search(text), "kind=%v", kind)
+		}
+	}
+}
+
+func TestSearchParallelWithoutSync(t *testing.T) {
+	// the scanners of the parts are never clean at the same offsets as the scanner
+	// of the text, the parts are searched again
+	for _, kind := range allMatchKinds {
+		trie := NewFlashKeywords(true)
+		trie.SetMatchKind(kind)
+		trie.Add("aa")
+		trie.Add(strings.Repeat("a", 3) + "b")
+		text := strings.Repeat("a", 100001)
+		expected := trie.Search(text)
+		assert.Equal(t, trie.SearchParallel(text, 4), expected, "kind=%v", kind)
+	}
+}
+
+func TestSearchParallelConcurrentGreedy(t *testing.T) {
+	// `Greedy` doesn't build the links: the calls only read the trie (see `go test -race`)
+	trie := newStreamTestTrie(Greedy, true)
+	text := strings.Repeat(streamTestText+"\n", 1000)
+	results := make([][]Result, 4)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = trie.SearchParallel(text, 4)
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, trie.isLinked(), false)
+	for i := range results {
+		assert.Equal(t, results[i], trie.Search(text), i)
+	}
+}
@@ -0,0 +1,91 @@
+package flashtext
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// Position locates a key found in the text for the UIs which don't count in bytes,
+// the spans are half-open ranges like the `Start` and `End` of the `Result`
+type Position struct {
+	RuneStart   int // span in runes (Unicode code points)
+	RuneEnd     int
+	UTF16Start  int // span in UTF-16 code units
+	UTF16End    int
+	Line        int // line of the start of the key, starting at 1
+	Column      int // column in runes of the start of the key, starting at 1
+	UTF16Column int // column in UTF-16 code units of the start of the key, starting at 1
+}
+
+// Enable or disable the computation of the `Position` of the results of `Search`
+func (tree *FlashKeywords) SetTrackPositions(enabled bool) {
+	tree.trackPositions = enabled
+}
+
+// textCursor moves forward in the text counting the runes, the UTF-16 code units and the lines
+type textCursor struct {
+	offset         int
+	runes          int
+	utf16          int
+	line           int
+	lineStartRunes int // nbr of runes before the current line
+	lineStartUTF16 int // nbr of UTF-16 code units before the current line
+}
+
+func newTextCursor() *textCursor {
+	return &textCursor{line: 1}
+}
+
+// advance moves the cursor to the byte offset `to` of the text
+func (c *textCursor) advance(text string, to int) {
+	for c.offset < to {
+		char, size := utf8.DecodeRuneInString(text[c.offset:])
+		c.offset += size
+		c.runes++
+		if char > 0xFFFF {
+			// encoded as a surrogate pair
+			c.utf16 += 2
+		} else {
+			c.utf16++
+		}
+		if char == '\n' {
+			c.line++
+			c.lineStartRunes = c.runes
+			c.lineStartUTF16 = c.utf16
+		}
+	}
+}
+
+// locate fills the `Position` of the results with a single pass over the text
+func locate(text string, res []Result) {
+	// the starts and ends of the results in the order of the text
+	type event struct {
+		offset int
+		idx    int
+		isEnd  bool
+	}
+	events := make([]event, 0, 2*len(res))
+	for i := range res {
+		res[i].Position = &Position{}
+		events = append(events, event{res[i].Start, i, false}, event{res[i].End, i, true})
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].offset < events[j].offset
+	})
+
+	cursor := newTextCursor()
+	for _, e := range events {
+		cursor.advance(text, e.offset)
+		pos := res[e.idx].Position
+		if e.isEnd {
+			pos.RuneEnd = cursor.runes
+			pos.UTF16End = cursor.utf16
+		} else {
+			pos.RuneStart = cursor.runes
+			pos.UTF16Start = cursor.utf16
+			pos.Line = cursor.line
+			pos.Column = cursor.runes - cursor.lineStartRunes + 1
+			pos.UTF16Column = cursor.utf16 - cursor.lineStartUTF16 + 1
+		}
+	}
+}
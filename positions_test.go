@@ -0,0 +1,70 @@
+package flashtext
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultSpanIsHalfOpen(t *testing.T) {
+	trie := NewFlashKeywords(false)
+	keys := []string{"北京", "été", "🔥", "a"}
+	for _, k := range keys {
+		trie.Add(k)
+	}
+	text := "北京 en été 🔥 a"
+	res := trie.Search(text)
+	assert.Equal(t, len(res), len(keys))
+	for i := range res {
+		assert.Equal(t, text[res[i].Start:res[i].End], keys[i])
+		assert.Equal(t, res[i].Position == nil, true)
+	}
+	t.Logf("res: %v", res)
+}
+
+func TestTrackPositions(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.SetTrackPositions(true)
+	trie.SetMatchKind(AllOverlapping)
+	keys := []string{"🔥fire", "fire", "北京", "line"}
+	for _, k := range keys {
+		trie.Add(k)
+	}
+	text := "first line\n🔥fire in 北京\r\n\nlast line 🔥fire"
+	res := trie.Search(text)
+	assert.Equal(t, len(res), 7)
+	lines := strings.Split(text, "\n")
+	for _, r := range res {
+		pos := r.Position
+		assert.Equal(t, pos.RuneStart, utf8.RuneCountInString(text[:r.Start]))
+		assert.Equal(t, pos.RuneEnd, utf8.RuneCountInString(text[:r.End]))
+		assert.Equal(t, pos.UTF16Start, len(utf16.Encode([]rune(text[:r.Start]))))
+		assert.Equal(t, pos.UTF16End, len(utf16.Encode([]rune(text[:r.End]))))
+		// the line and column point to the key
+		line := []rune(lines[pos.Line-1])
+		keyRunes := []rune(r.Key)
+		assert.Equal(t, string(line[pos.Column-1:pos.Column-1+len(keyRunes)]), r.Key)
+		assert.Equal(t, pos.UTF16Column, len(utf16.Encode(line[:pos.Column-1]))+1)
+		t.Logf("key: %v position: %+v", r.Key, *pos)
+	}
+	first := res[0].Position
+	assert.Equal(t, *first, Position{
+		RuneStart: 6, RuneEnd: 10, UTF16Start: 6, UTF16End: 10, Line: 1, Column: 7, UTF16Column: 7,
+	})
+	fire := res[1].Position
+	assert.Equal(t, *fire, Position{
+		RuneStart: 11, RuneEnd: 16, UTF16Start: 11, UTF16End: 17, Line: 2, Column: 1, UTF16Column: 1,
+	})
+	// the surrogate pair of "🔥" before the key on the line: the columns differ
+	beijing := res[3].Position
+	assert.Equal(t, res[3].Key, "北京")
+	assert.Equal(t, *beijing, Position{
+		RuneStart: 20, RuneEnd: 22, UTF16Start: 21, UTF16End: 23, Line: 2, Column: 10, UTF16Column: 11,
+	})
+	lastFire := res[6].Position
+	assert.Equal(t, lastFire.Line, 4)
+	assert.Equal(t, lastFire.UTF16Column-lastFire.Column, 1)
+}
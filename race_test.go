@@ -0,0 +1,8 @@
+//go:build race
+// +build race
+
+package flashtext
+
+// raceEnabled is true under `go test -race`, which makes `sync.Pool` drop some of the
+// items put back and allocate again
+const raceEnabled = true
@@ -0,0 +1,184 @@
+package flashtext
+
+// The trie is path-compressed (radix tree): a chain of nodes which are not the end of a key
+// and have a single child is stored as one node, the runes of the chain being the `label`
+// of the edge leading to the node. Apart from the root, every node is the end of a key or
+// has several children. The walks of the trie still move one rune at a time, the position
+// inside a label being a state of the walk like a node (see `trieState`).
+
+// trieState is a position of a walk in the trie: `pos` runes of the label of `node` are
+// consumed, the node itself is reached when `pos == len(node.label)`. The states of a
+// `flatTrie` have no `node` and their node id in `pos`, see `flatWalker`
+type trieState struct {
+	node *TrieNode
+	pos  int
+}
+
+// stateLinks are the links of the Aho-Corasick automaton of a state: `failure` is the state
+// of its longest proper suffix in the trie and `output` the node of its longest proper suffix
+// which is a key
+type stateLinks struct {
+	failure trieState
+	output  *TrieNode
+}
+
+// Statistics about the size of the trie:
+//   - `Nodes`: the nbr of nodes, the root included.
+//   - `Edges`: the nbr of edges between the nodes.
+//   - `Runes`: the nbr of runes on the edges, which is the nbr of edges without
+//     the path compression.
+type TrieStats struct {
+	Nodes int
+	Edges int
+	Runes int
+}
+
+// Returns the statistics about the size of the trie, `Runes - Edges` nodes are saved
+// by the path compression
+func (tree *FlashKeywords) Stats() TrieStats {
+	stats := TrieStats{Nodes: tree.nbrNodes, Edges: tree.nbrNodes - 1}
+	stack := []*TrieNode{tree.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, child := range node.children {
+			stats.Runes += 1 + len(child.label)
+			stack = append(stack, child)
+		}
+	}
+	return stats
+}
+
+// next returns the state reached from the state `s` by the rune `char`, false if there is none
+func (s trieState) next(char rune) (trieState, bool) {
+	if s.pos < len(s.node.label) {
+		if s.node.label[s.pos] == char {
+			return trieState{s.node, s.pos + 1}, true
+		}
+		return trieState{}, false
+	}
+	child, ok := s.node.children[char]
+	return trieState{node: child}, ok
+}
+
+// isWord reports whether the state is the node of a key
+func (s trieState) isWord() bool {
+	return s.pos == len(s.node.label) && s.node.isWord
+}
+
+// depth returns the nbr of runes from the root to the state
+func (s trieState) depth() int {
+	return s.node.depth - len(s.node.label) + s.pos
+}
+
+// links returns the links of the automaton of the state, computed by `buildLinks`
+func (s trieState) links() *stateLinks {
+	return &s.node.links[s.pos]
+}
+
+// radixWalker is the `trieWalker` of the `FlashKeywords`
+type radixWalker struct{}
+
+func (radixWalker) next(s trieState, char rune) (trieState, bool) {
+	return s.next(char)
+}
+
+func (radixWalker) isWord(s trieState) bool {
+	return s.isWord()
+}
+
+func (radixWalker) keep(s trieState) bool {
+	return s.node.keep
+}
+
+func (radixWalker) depth(s trieState) int {
+	return s.depth()
+}
+
+func (radixWalker) failure(s trieState) trieState {
+	return s.links().failure
+}
+
+func (radixWalker) output(s trieState) (trieState, bool) {
+	node := s.links().output
+	if node == nil {
+		return trieState{}, false
+	}
+	return trieState{node, len(node.label)}, true
+}
+
+func (radixWalker) match(s trieState, start int, end int, isPrefix bool) match {
+	return match{node: s.node, order: s.node.order, start: start, end: end, isPrefix: isPrefix}
+}
+
+// lookup returns the node reached by `word`, nil if `word` doesn't end on a node.
+// The nodes from the root to the node are appended to `path` if not nil
+func (tree *FlashKeywords) lookup(word string, path *[]*TrieNode) *TrieNode {
+	state := trieState{node: tree.root}
+	if path != nil {
+		*path = append(*path, state.node)
+	}
+	for _, char := range word {
+		nextState, ok := state.next(char)
+		if !ok {
+			return nil
+		}
+		if path != nil && nextState.node != state.node {
+			*path = append(*path, nextState.node)
+		}
+		state = nextState
+	}
+	if state.pos < len(state.node.label) {
+		return nil
+	}
+	return state.node
+}
+
+// splitNode splits the label of `node` after its `n` first runes: `node` ends there
+// and the rest of the label with the key and the children of `node` go to a new child
+func (tree *FlashKeywords) splitNode(node *TrieNode, n int) {
+	tail := &TrieNode{
+		selfRune:  node.label[n],
+		label:     node.label[n+1:],
+		children:  node.children,
+		isWord:    node.isWord,
+		cleanWord: node.cleanWord,
+		keep:      node.keep,
+		key:       node.key,
+		depth:     node.depth,
+		order:     node.order,
+	}
+	*node = TrieNode{
+		selfRune: node.selfRune,
+		label:    node.label[:n:n],
+		children: map[rune]*TrieNode{tail.selfRune: tail},
+		depth:    node.depth - (len(node.label) - n),
+	}
+	tree.nbrNodes++
+	tree.linked = 0
+}
+
+// mergeChild compresses `node`, which is not the end of a key, with its single child:
+// the label of the child is appended to the one of `node` which takes its place
+func (tree *FlashKeywords) mergeChild(node *TrieNode) {
+	var child *TrieNode
+	for _, child = range node.children {
+	}
+	label := make([]rune, 0, len(node.label)+1+len(child.label))
+	label = append(label, node.label...)
+	label = append(label, child.selfRune)
+	label = append(label, child.label...)
+	*node = TrieNode{
+		selfRune:  node.selfRune,
+		label:     label,
+		children:  child.children,
+		isWord:    child.isWord,
+		cleanWord: child.cleanWord,
+		keep:      child.keep,
+		key:       child.key,
+		depth:     child.depth,
+		order:     child.order,
+	}
+	tree.nbrNodes--
+	tree.linked = 0
+}
@@ -0,0 +1,126 @@
+package flashtext
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// checkRadix checks that the trie is fully compressed: apart from the root every node
+// is a key or has several children, and `nbrNodes` is the nbr of nodes
+func checkRadix(t *testing.T, trie *FlashKeywords) {
+	nbrNodes := 0
+	stack := []*TrieNode{trie.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		nbrNodes++
+		if node != trie.root && !node.isWord {
+			assert.Greater(t, len(node.children), 1, "node %q", node.selfRune)
+		}
+		for _, child := range node.children {
+			assert.Equal(t, child.depth, node.depth+1+len(child.label))
+			stack = append(stack, child)
+		}
+	}
+	assert.Equal(t, trie.nbrNodes, nbrNodes)
+}
+
+func TestRadixSplitAndMerge(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.Add("romane")
+	assert.Equal(t, trie.Stats(), TrieStats{Nodes: 2, Edges: 1, Runes: 6})
+	// `romanus` leaves the label `romane` after `roman`
+	trie.Add("romanus")
+	assert.Equal(t, trie.Stats(), TrieStats{Nodes: 4, Edges: 3, Runes: 8})
+	// `rom` ends inside the label `roman`
+	trie.Add("rom")
+	assert.Equal(t, trie.Stats(), TrieStats{Nodes: 5, Edges: 4, Runes: 8})
+	checkRadix(t, trie)
+	assert.Equal(t, trie.Contains("roma"), false)
+	assert.Equal(t, trie.Contains("roman"), false)
+	assert.Equal(t, trie.RemoveKey("roma"), false)
+	assert.Equal(t, trie.RemoveKey("romanusx"), false)
+
+	// `roman` is merged with `e` when `romanus` is removed
+	assert.Equal(t, trie.RemoveKey("romanus"), true)
+	assert.Equal(t, trie.Stats(), TrieStats{Nodes: 3, Edges: 2, Runes: 6})
+	assert.Equal(t, trie.RemoveKey("rom"), true)
+	assert.Equal(t, trie.Stats(), TrieStats{Nodes: 2, Edges: 1, Runes: 6})
+	checkRadix(t, trie)
+	assert.Equal(t, trie.GetAllKeywords(), map[string]string{"romane": ""})
+	assert.Equal(t, len(trie.Search("romane romanus rom")), 1)
+}
+
+func TestRadixSearchInsideLabels(t *testing.T) {
+	// the walk breaks in the middle of the label `new york city`: the greedy walk
+	// goes back to the root and loses `york`, the automaton follows the failure link
+	// of the state inside the label
+	expected := map[MatchKind]string{
+		Greedy:          "new york cit, NYC",
+		AllOverlapping:  "new Y cit, NEW Y CITY",
+		LeftmostLongest: "new Y cit, NYC",
+		LeftmostFirst:   "new Y cit, NYC",
+		Shortest:        "new Y cit, NEW Y CITY",
+	}
+	for kind, newText := range expected {
+		trie := NewFlashKeywords(false)
+		trie.SetMatchKind(kind)
+		trie.AddKeyWord("New York City", "NYC")
+		trie.AddKeyWord("York", "Y")
+		assert.Equal(t, trie.Replace("new york cit, NEW YORK CITY"), newText, "kind=%v", kind)
+	}
+}
+
+func TestRadixRandomUpdates(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	randomString := func(size int) string {
+		letters := []rune("abé")
+		word := make([]rune, size)
+		for i := range word {
+			word[i] = letters[r.Intn(len(letters))]
+		}
+		return string(word)
+	}
+	for round := 0; round < 50; round++ {
+		trie := NewFlashKeywords(true)
+		trie.SetMatchKind(AllOverlapping)
+		keysSet := make(map[string]bool)
+		for i := 0; i < 40; i++ {
+			key := randomString(1 + r.Intn(6))
+			if r.Intn(3) == 0 {
+				assert.Equal(t, trie.RemoveKey(key), keysSet[key])
+				delete(keysSet, key)
+			} else {
+				trie.Add(key)
+				keysSet[key] = true
+			}
+		}
+		checkRadix(t, trie)
+		assert.Equal(t, trie.Size(), len(keysSet))
+
+		keys := make([]string, 0, len(keysSet))
+		for k := range keysSet {
+			keys = append(keys, k)
+			assert.Equal(t, trie.Contains(k), true)
+		}
+		text := randomString(100)
+		res := trie.Search(text)
+		expected := bruteForceSearch(keys, text)
+		assert.Equal(t, len(res), len(expected))
+		for i := 0; i < len(res) && i < len(expected); i++ {
+			assert.Equal(t, res[i].Start, expected[i][0])
+			assert.Equal(t, res[i].End, expected[i][1])
+		}
+
+		// the binary format stores a node per rune and is compressed again
+		data, err := trie.MarshalBinary()
+		assert.Nil(t, err)
+		decoded := NewFlashKeywords(true)
+		assert.Nil(t, decoded.UnmarshalBinary(data))
+		checkRadix(t, decoded)
+		assert.Equal(t, decoded.Stats(), trie.Stats())
+		assert.Equal(t, decoded.Search(text), res)
+	}
+}
@@ -0,0 +1,127 @@
+package flashtext
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Write the keys of the trie with their `cleanWord` to `w` encoded with the `format`,
+// the dictionary written is loaded back by `AddFromReader` with the same `format` into
+// the same keys. The keys are written in the sorted order so the output only depends on
+// the keys and not on the order they were added. With `CSV` and a negative `CleanColumn`
+// only the keys are written. An error is returned for a key or a `cleanWord` which can't be
+// encoded in the `format` (like a key containing the `Separator` of the `Lines`).
+func (tree *FlashKeywords) WriteDictionary(w io.Writer, format Format) error {
+	key2Clean := tree.GetAllKeywords()
+	keys := make([]string, 0, len(key2Clean))
+	for key := range key2Clean {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	switch format.Kind {
+	case Lines:
+		return writeLines(w, keys, key2Clean, format)
+	case JSON:
+		clean2Keys := make(map[string][]string)
+		for _, key := range keys {
+			clean := key2Clean[key]
+			clean2Keys[clean] = append(clean2Keys[clean], key)
+		}
+		// the keys of the map are sorted by the encoder
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(clean2Keys)
+	case CSV:
+		return writeCSV(w, keys, key2Clean, format)
+	}
+	return fmt.Errorf("flashtext: unknown format kind %d", format.Kind)
+}
+
+// Write the keys of the trie to the file `filePath` like `WriteDictionary`,
+// the file is created or truncated
+func (tree *FlashKeywords) SaveToFile(filePath string, format Format) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	if err := tree.WriteDictionary(file, format); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func writeLines(w io.Writer, keys []string, key2Clean map[string]string, format Format) error {
+	sep := format.Separator
+	if sep == "" {
+		sep = separator
+	}
+
+	buf := bufio.NewWriter(w)
+	for _, key := range keys {
+		clean := key2Clean[key]
+		switch {
+		case strings.TrimSpace(key) == "" ||
+			format.Comment != 0 && strings.HasPrefix(key, string(format.Comment)):
+			return fmt.Errorf("flashtext: the key %q would be ignored when loaded", key)
+		case strings.ContainsAny(key+clean, "\r\n"):
+			return fmt.Errorf("flashtext: the key %q or its clean word contain a line break", key)
+		case strings.Contains(key, sep) || strings.Contains(clean, sep):
+			return fmt.Errorf("flashtext: the key %q or its clean word contain the separator %q", key, sep)
+		}
+
+		buf.WriteString(key)
+		if clean != "" {
+			buf.WriteString(sep)
+			buf.WriteString(clean)
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.Flush()
+}
+
+func writeCSV(w io.Writer, keys []string, key2Clean map[string]string, format Format) error {
+	if format.KeyColumn < 0 || format.KeyColumn == format.CleanColumn {
+		return fmt.Errorf("flashtext: invalid CSV columns %d and %d", format.KeyColumn, format.CleanColumn)
+	}
+	width := format.KeyColumn + 1
+	if format.CleanColumn >= width {
+		width = format.CleanColumn + 1
+	}
+	record := make([]string, width)
+	writer := csv.NewWriter(w)
+	if format.Comma != 0 {
+		writer.Comma = format.Comma
+	}
+
+	if format.Header {
+		record[format.KeyColumn] = "key"
+		if format.CleanColumn >= 0 {
+			record[format.CleanColumn] = "cleanWord"
+		}
+		writer.Write(record)
+	}
+	for _, key := range keys {
+		if strings.TrimSpace(key) == "" || strings.Contains(key+key2Clean[key], "\r") {
+			return fmt.Errorf("flashtext: the key %q can't be written in a CSV record", key)
+		}
+		record[format.KeyColumn] = key
+		if format.CleanColumn >= 0 {
+			record[format.CleanColumn] = key2Clean[key]
+		}
+		if format.Comment != 0 && strings.HasPrefix(record[0], string(format.Comment)) {
+			return fmt.Errorf("flashtext: the record of the key %q would be ignored when loaded", key)
+		}
+		writer.Write(record)
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,119 @@
+package flashtext
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newSaveTestTrie() *FlashKeywords {
+	trie := NewFlashKeywords(false)
+	trie.AddFromMap(map[string][]string{
+		"java":              {"java_2e", "java programing"},
+		"Software Engineer": {"SWE", "Backend Engineer", "développeur"},
+		"C, C++":            {"c++", `"cpp"`},
+	})
+	trie.Add("Banana")
+	trie.Add("北京")
+	trie.AddKeyWord("<b>", "bold & strong")
+	return trie
+}
+
+func TestWriteDictionaryRoundTrip(t *testing.T) {
+	tsvColumns := TSVFormat
+	tsvColumns.KeyColumn = 2
+	tsvColumns.CleanColumn = 0
+	tsvColumns.Header = true
+	customLines := LineFormat
+	customLines.Separator = "\t->\t"
+	customLines.Comment = '#'
+
+	trie := newSaveTestTrie()
+	for _, format := range []Format{LineFormat, customLines, JSONFormat, CSVFormat, tsvColumns} {
+		var buf bytes.Buffer
+		err := trie.WriteDictionary(&buf, format)
+		assert.Nil(t, err)
+
+		loaded := NewFlashKeywords(false)
+		skipped, err := loaded.AddFromReaderWithOptions(bytes.NewReader(buf.Bytes()), format, LoadOptions{FailOnMalformed: true})
+		assert.Nil(t, err)
+		assert.Equal(t, len(skipped), 0)
+		assert.Equal(t, loaded.GetAllKeywords(), trie.GetAllKeywords(), "format=%v", format)
+
+		// load => save gives back the same output
+		var again bytes.Buffer
+		err = loaded.WriteDictionary(&again, format)
+		assert.Nil(t, err)
+		assert.Equal(t, again.String(), buf.String())
+		t.Logf("format=%v output:\n%s", format, buf.String())
+	}
+}
+
+func TestWriteDictionarySortedOrder(t *testing.T) {
+	first := NewFlashKeywords(true)
+	second := NewFlashKeywords(true)
+	keys := []string{"pypy", "java", "python2", "Banana", "java_2e"}
+	for i := range keys {
+		first.AddKeyWord(keys[i], "clean")
+		second.AddKeyWord(keys[len(keys)-1-i], "clean")
+	}
+	var buf1, buf2 bytes.Buffer
+	assert.Nil(t, first.WriteDictionary(&buf1, LineFormat))
+	assert.Nil(t, second.WriteDictionary(&buf2, LineFormat))
+	assert.Equal(t, buf1.String(), buf2.String())
+	assert.Equal(t, buf1.String(), "Banana=>clean\njava=>clean\njava_2e=>clean\npypy=>clean\npython2=>clean\n")
+
+	buf1.Reset()
+	assert.Nil(t, first.WriteDictionary(&buf1, JSONFormat))
+	assert.Equal(t, buf1.String(), "{\n  \"clean\": [\n    \"Banana\",\n    \"java\",\n    \"java_2e\",\n    \"pypy\",\n    \"python2\"\n  ]\n}\n")
+}
+
+func TestWriteDictionaryUnencodable(t *testing.T) {
+	withComment := CSVFormat
+	withComment.Comment = '#'
+	testdata := []struct {
+		key    string
+		clean  string
+		format Format
+	}{
+		{"a=>b", "", LineFormat},
+		{"java", "=>", LineFormat},
+		{"two\nlines", "", LineFormat},
+		{"  ", "", LineFormat},
+		{"#tag", "", withComment},
+		{"a\rb", "", CSVFormat},
+	}
+	for _, item := range testdata {
+		trie := NewFlashKeywords(true)
+		trie.AddKeyWord(item.key, item.clean)
+		var buf bytes.Buffer
+		err := trie.WriteDictionary(&buf, item.format)
+		assert.NotNil(t, err, item.key)
+		t.Logf("err: %v", err)
+	}
+	// the key is quoted in the CSV format
+	trie := NewFlashKeywords(true)
+	trie.Add("two\nlines")
+	var buf bytes.Buffer
+	assert.Nil(t, trie.WriteDictionary(&buf, CSVFormat))
+	assert.Equal(t, buf.String(), "\"two\nlines\",\n")
+}
+
+func TestSaveToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	trie := newSaveTestTrie()
+	err := trie.SaveToFile(path, LineFormat)
+	assert.Nil(t, err)
+
+	loaded := NewFlashKeywords(false)
+	err = loaded.AddFromFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, loaded.GetAllKeywords(), trie.GetAllKeywords())
+
+	err = trie.SaveToFile(filepath.Join(path, "notADir.txt"), LineFormat)
+	assert.NotNil(t, err)
+	assert.Equal(t, strings.Contains(err.Error(), "notADir.txt"), true)
+}
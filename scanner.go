@@ -0,0 +1,345 @@
+package flashtext
+
+import (
+	"sync"
+	"unicode/utf8"
+)
+
+// trieWalker gives the scanner access to the states of a trie, so the `FlashKeywords` and
+// the `flatTrie` of the binary dictionaries share the same matching
+type trieWalker interface {
+	// next returns the state reached from `s` by the rune `char`, false if there is none
+	next(s trieState, char rune) (trieState, bool)
+	// isWord reports whether `s` is the end of a key
+	isWord(s trieState) bool
+	// keep reports whether the key ending at `s` is the prefix of other keys
+	keep(s trieState) bool
+	// depth returns the nbr of runes from the root to `s`
+	depth(s trieState) int
+	// failure and output are the links of the automaton: the state of the longest proper
+	// suffix in the trie and the state of the longest proper suffix which is a key, if any
+	failure(s trieState) trieState
+	output(s trieState) (trieState, bool)
+	// match returns the occurrence of the key ending at `s`
+	match(s trieState, start int, end int, isPrefix bool) match
+}
+
+// scanSettings are the settings of the trie which the scanner depends on
+type scanSettings struct {
+	matchKind     MatchKind
+	caseSensitive bool
+	boundary      bool
+	wordChars     map[rune]bool
+	maxDepth      int // nbr of runes of the longest key, only needed by the automaton kinds
+}
+
+// scanner runs the selected `MatchKind` over a text given rune by rune, so the texts in
+// memory and the streams share the same matching. The decisions needing to look one rune
+// ahead (word boundary, `IsPrefix`) are made when the next rune is given or at `finish`.
+// The keys found are given to the `fn` passed to `step` and `finish`, which is never stored:
+// a scanner kept on the stack walking the trie with the `Greedy` kind doesn't allocate.
+type scanner struct {
+	walker trieWalker
+	scanSettings
+	root trieState
+
+	// current state of the trie walk (`Greedy`) or of the automaton
+	state trieState
+
+	// Greedy: start of the current walk and the key reached by the last rune
+	start   int
+	word    trieState
+	hasWord bool
+
+	// automaton kinds: the last runes, the occurrences ending with the last rune and the
+	// byte offset before which the next occurrences can't start
+	runes    runeRing
+	ends     []scanEnd
+	limit    int
+	resolver resolver // only used by the non overlapping kinds
+
+	prevWord bool // the last rune is a word rune: a key can't start after it in boundary mode
+}
+
+// scanEnd is an occurrence ending with the last rune and the state of its key
+type scanEnd struct {
+	m     match
+	state trieState
+}
+
+// scannerPool keeps the scanners whose buffers are reused from a search to the next,
+// see `reset`
+var scannerPool = sync.Pool{New: func() interface{} { return new(scanner) }}
+
+// reset makes `s` a new scanner walking the trie of `walker` from `root`, the buffers of
+// the automaton kinds are reused when they are large enough
+func (s *scanner) reset(walker trieWalker, root trieState, settings scanSettings) {
+	*s = scanner{
+		walker:       walker,
+		scanSettings: settings,
+		root:         root,
+		state:        root,
+		runes:        s.runes,
+		ends:         s.ends[:0],
+		resolver:     resolver{pending: s.resolver.pending[:0]},
+	}
+	if settings.matchKind != Greedy {
+		s.runes.reset(settings.maxDepth)
+		s.resolver.kind = settings.matchKind
+	}
+}
+
+// newScanner returns a scanner finding the keys with the selected `MatchKind`
+func (tree *FlashKeywords) newScanner() scanner {
+	var s scanner
+	tree.resetScanner(&s)
+	return s
+}
+
+// resetScanner makes `s` a new scanner of the trie, see `newScanner`
+func (tree *FlashKeywords) resetScanner(s *scanner) {
+	if tree.matchKind != Greedy {
+		tree.ensureLinks()
+	}
+	s.reset(radixWalker{}, trieState{node: tree.root}, scanSettings{
+		matchKind:     tree.matchKind,
+		caseSensitive: tree.caseSensitive,
+		boundary:      tree.boundary,
+		wordChars:     tree.wordChars,
+		maxDepth:      tree.maxDepth,
+	})
+}
+
+// emit reports an occurrence found, through the resolver for the non overlapping kinds
+func (s *scanner) emit(m match, fn func(m match)) {
+	switch s.matchKind {
+	case Greedy, AllOverlapping:
+		fn(m)
+	default:
+		s.resolver.add(m)
+	}
+}
+
+// step gives the next rune `char` of the text found at the byte offset `offset`,
+// `fn` is called for the keys which can be reported now
+func (s *scanner) step(char rune, offset int, size int, fn func(m match)) {
+	folded := foldRune(char, s.caseSensitive)
+	if s.matchKind == Greedy {
+		s.stepGreedy(char, folded, offset, fn)
+	} else {
+		s.stepAutomaton(char, folded, offset, size, fn)
+	}
+	if s.boundary {
+		s.prevWord = isWordRuneWith(s.wordChars, char)
+	}
+}
+
+// finish is called at the end of the text, `end` is the byte length of the text
+func (s *scanner) finish(end int, fn func(m match)) {
+	if s.matchKind == Greedy {
+		if s.hasWord {
+			s.emit(s.walker.match(s.word, s.start, end, false), fn)
+			s.hasWord = false
+		}
+		return
+	}
+
+	s.flushEnds(0, 0, true, fn)
+	s.resolver.flush(fn)
+}
+
+// scan gives all the runes of the text and finishes it
+func (s *scanner) scan(text string, fn func(m match)) {
+	for idx := 0; idx < len(text); {
+		char, size := rune(text[idx]), 1
+		if char >= utf8.RuneSelf {
+			char, size = utf8.DecodeRuneInString(text[idx:])
+		}
+		s.step(char, idx, size, fn)
+		idx += size
+	}
+	s.finish(len(text), fn)
+}
+
+// safe returns the byte offset before which no key will be reported anymore,
+// `offset` is the end of the runes given so far
+func (s *scanner) safe(offset int) int {
+	if s.matchKind == Greedy {
+		if s.state != s.root {
+			return s.start
+		}
+		return offset
+	}
+
+	safe := s.limit
+	if s.runes.count == 0 {
+		safe = offset
+	}
+	for _, e := range s.ends {
+		if e.m.start < safe {
+			safe = e.m.start
+		}
+	}
+	for _, m := range s.resolver.pending {
+		if m.start < safe {
+			safe = m.start
+		}
+	}
+	return safe
+}
+
+// wordEnd reports whether a key can end right before the rune `char`
+func (s *scanner) wordEnd(char rune) bool {
+	return !s.boundary || !isWordRuneWith(s.wordChars, char)
+}
+
+// isPrefix reports whether the key ending at the state `word` is the prefix of another
+// key continuing with the rune `folded`
+func (s *scanner) isPrefix(word trieState, folded rune) bool {
+	if !s.walker.keep(word) {
+		return false
+	}
+	_, ok := s.walker.next(word, folded)
+	return ok
+}
+
+func (s *scanner) stepGreedy(char rune, folded rune, offset int, fn func(m match)) {
+	if s.hasWord && s.wordEnd(char) {
+		// possibility to be a prefix of another continous word
+		isPrefix := s.isPrefix(s.word, folded)
+		s.emit(s.walker.match(s.word, s.start, offset, isPrefix), fn)
+		if !isPrefix {
+			// go back to root with 2 conditions (see TestGoBackToRootTrick):
+			// 	- simple one if keep=false (isPrefix=false by default)
+			// 	- keep can be true but when we look one step ahead
+			// 	  no node is founded => Go back to root
+			s.state = s.root
+		}
+	}
+	s.hasWord = false
+
+	for {
+		if s.state == s.root {
+			if s.boundary && s.prevWord {
+				// a key can't start in the middle of a word
+				return
+			}
+			s.start = offset
+		}
+
+		nextState, ok := s.walker.next(s.state, folded)
+		if !ok {
+			if s.boundary && s.state != s.root {
+				// the rune breaking the walk may still be the start of another key
+				s.state = s.root
+				continue
+			}
+			s.state = s.root
+			return
+		}
+		s.state = nextState
+		if s.walker.isWord(nextState) {
+			s.word, s.hasWord = nextState, true
+		}
+		return
+	}
+}
+
+func (s *scanner) stepAutomaton(char rune, folded rune, offset int, size int, fn func(m match)) {
+	s.flushEnds(char, folded, false, fn)
+
+	s.runes.push(offset, !s.boundary || !s.prevWord)
+	state := s.state
+	for {
+		if nextState, ok := s.walker.next(state, folded); ok {
+			state = nextState
+			break
+		}
+		if state == s.root {
+			break
+		}
+		state = s.walker.failure(state)
+	}
+	s.state = state
+
+	end := offset + size
+	word, ok := state, state != s.root && s.walker.isWord(state)
+	if !ok {
+		word, ok = s.walker.output(state)
+	}
+	for ; ok; word, ok = s.walker.output(word) {
+		start, canStart := s.runes.start(s.walker.depth(word))
+		if !canStart {
+			// a key can't start in the middle of a word
+			continue
+		}
+		s.ends = append(s.ends, scanEnd{m: s.walker.match(word, start, end, false), state: word})
+	}
+	if s.maxDepth > 1 {
+		s.limit, _ = s.runes.start(s.maxDepth - 1)
+	} else {
+		s.limit = end
+	}
+}
+
+// flushEnds reports the occurrences ending with the last rune now that the
+// next rune `char` is known, `eof` is true at the end of the text
+func (s *scanner) flushEnds(char rune, folded rune, eof bool, fn func(m match)) {
+	if len(s.ends) > 0 {
+		wordEnd := eof || s.wordEnd(char)
+		for _, e := range s.ends {
+			if !wordEnd {
+				continue
+			}
+			if !eof {
+				e.m.isPrefix = s.isPrefix(e.state, folded)
+			}
+			s.emit(e.m, fn)
+		}
+		s.ends = s.ends[:0]
+	}
+	s.resolver.advance(s.limit, fn)
+}
+
+// runeRing keeps the byte offsets of the last runes given to the scanner,
+// enough to find the start of the longest key ending at the current rune
+type runeRing struct {
+	offsets  []int
+	canStart []bool // a key can start at the rune (boundary mode)
+	count    int
+}
+
+// reset empties the ring and makes room for `size` runes
+func (r *runeRing) reset(size int) {
+	if size < 1 {
+		size = 1
+	}
+	if cap(r.offsets) < size {
+		r.offsets, r.canStart = make([]int, size), make([]bool, size)
+	}
+	r.offsets, r.canStart = r.offsets[:size], r.canStart[:size]
+	r.count = 0
+}
+
+func (r *runeRing) push(offset int, canStart bool) {
+	i := r.count % len(r.offsets)
+	r.offsets[i] = offset
+	r.canStart[i] = canStart
+	r.count++
+}
+
+// start returns the byte offset of the first of the last `n` runes
+// and whether a key can start at it
+func (r *runeRing) start(n int) (int, bool) {
+	if n > r.count {
+		n = r.count
+	}
+	i := (r.count - n) % len(r.offsets)
+	return r.offsets[i], r.canStart[i]
+}
+
+// matches calls `fn` for every key found in the text with the selected `MatchKind`
+func (tree *FlashKeywords) matches(text string, fn func(m match)) {
+	s := tree.newScanner()
+	s.scan(text, fn)
+}
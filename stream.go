@@ -0,0 +1,134 @@
+package flashtext
+
+import (
+	"bufio"
+	"io"
+	"unicode/utf8"
+)
+
+// Search in the text read from `r` for the stored keys in the trie and calls `fn` for
+// every `Result` found, in the same order as `Search` would return them for the whole text.
+// The text is never loaded in memory: only the state of the trie walk is kept between the
+// reads, so the keys straddling two reads are found like the others. `Start` and `End` are
+// absolute byte offsets from the beginning of the reader, the `Position` is not computed.
+// The search stops at the first error returned by `fn` or by the reader and returns it.
+func (tree *FlashKeywords) SearchReader(r io.Reader, fn func(Result) error) error {
+	var fnErr error
+	report := func(m match) {
+		if fnErr == nil {
+			fnErr = fn(m.result())
+		}
+	}
+	s := tree.newScanner()
+
+	runeReader, ok := r.(io.RuneReader)
+	if !ok {
+		runeReader = bufio.NewReader(r)
+	}
+	offset := 0
+	for fnErr == nil {
+		char, size, err := runeReader.ReadRune()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		s.step(char, offset, size, report)
+		offset += size
+	}
+	if fnErr != nil {
+		return fnErr
+	}
+
+	s.finish(offset, report)
+	return fnErr
+}
+
+// Replace the keys found in the text read from `src` with their `cleanWord` like `Replace`
+// and writes the new text to `dst` as soon as possible: only the bytes which can still be
+// the beginning of a key are held back between the reads, so the memory used doesn't depend
+// on the size of the text. Returns the number of bytes written and the first error met.
+func (tree *FlashKeywords) ReplaceStream(dst io.Writer, src io.Reader) (int64, error) {
+	w := &streamReplacer{dst: dst}
+	s := tree.newScanner()
+
+	chunk := make([]byte, streamChunkSize)
+	offset := 0 // absolute offset of the next rune to give to the scanner
+	for {
+		n, err := src.Read(chunk)
+		w.buf = append(w.buf, chunk[:n]...)
+		eof := err == io.EOF
+		if err != nil && !eof {
+			return w.written, err
+		}
+
+		for offset < w.base+len(w.buf) {
+			p := w.buf[offset-w.base:]
+			if !eof && !utf8.FullRune(p) {
+				// the end of the rune is in the next read
+				break
+			}
+			char, size := utf8.DecodeRune(p)
+			s.step(char, offset, size, w.replace)
+			offset += size
+		}
+		if eof {
+			s.finish(offset, w.replace)
+			w.flush(offset)
+			return w.written, w.err
+		}
+		w.flush(s.safe(offset))
+		if w.err != nil {
+			return w.written, w.err
+		}
+	}
+}
+
+// size of the reads of `ReplaceStream`
+const streamChunkSize = 32 * 1024
+
+// streamReplacer writes the text given to the scanner with the keys replaced,
+// it keeps the bytes of the text from `lastChange` which are not written yet
+type streamReplacer struct {
+	dst        io.Writer
+	written    int64
+	err        error
+	buf        []byte
+	base       int // absolute offset of buf[0]
+	lastChange int // absolute offset of the end of the last replaced key
+}
+
+func (w *streamReplacer) replace(m match) {
+	if skipReplace(m, m.node.cleanWord, w.lastChange) {
+		return
+	}
+	w.write(w.buf[w.lastChange-w.base : m.start-w.base])
+	if w.err == nil {
+		n, err := io.WriteString(w.dst, m.node.cleanWord)
+		w.written += int64(n)
+		w.err = err
+	}
+	w.lastChange = m.end
+}
+
+// flush writes the text up to the absolute offset `safe` and drops it from the buffer
+func (w *streamReplacer) flush(safe int) {
+	if safe > w.lastChange {
+		w.write(w.buf[w.lastChange-w.base : safe-w.base])
+		w.lastChange = safe
+	}
+	// keep the unwritten bytes at the beginning of the buffer
+	n := copy(w.buf, w.buf[w.lastChange-w.base:])
+	w.buf = w.buf[:n]
+	w.base = w.lastChange
+}
+
+func (w *streamReplacer) write(p []byte) {
+	if w.err != nil || len(p) == 0 {
+		return
+	}
+	n, err := w.dst.Write(p)
+	w.written += int64(n)
+	w.err = err
+}
@@ -0,0 +1,178 @@
+package flashtext
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var allMatchKinds = []MatchKind{Greedy, AllOverlapping, LeftmostLongest, LeftmostFirst, Shortest}
+
+func newStreamTestTrie(kind MatchKind, boundary bool) *FlashKeywords {
+	trie := NewFlashKeywords(false)
+	trie.SetMatchKind(kind)
+	trie.SetBoundaryMode(boundary)
+	trie.AddKeyWord("java", "python")
+	trie.AddKeyWord("java programing", "skill")
+	trie.AddKeyWord("programing language", "PL")
+	trie.AddKeyWord("北京", "Beijing")
+	trie.AddKeyWord("🔥", "fire")
+	trie.Add("cat")
+	trie.Add("catch")
+	trie.Add("atc")
+	return trie
+}
+
+const streamTestText = "Java programing language in 北京🔥, catch the cat! javascript JAVA programing"
+
+func TestSearchReaderLikeSearch(t *testing.T) {
+	for _, kind := range allMatchKinds {
+		for _, boundary := range []bool{false, true} {
+			trie := newStreamTestTrie(kind, boundary)
+			expected := trie.Search(streamTestText)
+
+			// one byte per read: every key and every multi-byte rune straddles reads
+			var res []Result
+			err := trie.SearchReader(iotest.OneByteReader(strings.NewReader(streamTestText)), func(r Result) error {
+				res = append(res, r)
+				return nil
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, res, expected, "kind=%v boundary=%v", kind, boundary)
+			for _, r := range res {
+				assert.Equal(t, strings.ToLower(streamTestText[r.Start:r.End]), r.Key)
+			}
+			t.Logf("kind=%v boundary=%v res: %v", kind, boundary, res)
+		}
+	}
+}
+
+func TestSearchReaderAbsoluteOffsets(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.Add("needle")
+	chunk := strings.Repeat("hay ", 1000) + "needle "
+	text := strings.Repeat(chunk, 50)
+	count := 0
+	err := trie.SearchReader(iotest.HalfReader(strings.NewReader(text)), func(r Result) error {
+		assert.Equal(t, text[r.Start:r.End], "needle")
+		assert.Equal(t, r.Start, count*len(chunk)+4000)
+		count++
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, count, 50)
+}
+
+func TestSearchReaderStopEarly(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.Add("a")
+	errStop := errors.New("stop")
+	count := 0
+	err := trie.SearchReader(strings.NewReader("a a a a a"), func(r Result) error {
+		count++
+		if count == 2 {
+			return errStop
+		}
+		return nil
+	})
+	assert.Equal(t, err, errStop)
+	assert.Equal(t, count, 2)
+}
+
+func TestSearchReaderError(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.Add("a")
+	errRead := errors.New("read error")
+	err := trie.SearchReader(iotest.ErrReader(errRead), func(r Result) error {
+		return nil
+	})
+	assert.Equal(t, err, errRead)
+}
+
+func TestReplaceStreamLikeReplace(t *testing.T) {
+	for _, kind := range allMatchKinds {
+		for _, boundary := range []bool{false, true} {
+			trie := newStreamTestTrie(kind, boundary)
+			expected := trie.Replace(streamTestText)
+
+			var buf strings.Builder
+			n, err := trie.ReplaceStream(&buf, iotest.OneByteReader(strings.NewReader(streamTestText)))
+			assert.Nil(t, err)
+			assert.Equal(t, buf.String(), expected, "kind=%v boundary=%v", kind, boundary)
+			assert.Equal(t, n, int64(len(expected)))
+			t.Logf("kind=%v boundary=%v newText: %v", kind, boundary, buf.String())
+		}
+	}
+}
+
+func TestReplaceStreamInvalidUTF8(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.AddKeyWord("key", "KEY")
+	// the invalid bytes are copied as they are
+	text := "a\xffkey\xe4\xb8 key\xe4"
+	var buf strings.Builder
+	_, err := trie.ReplaceStream(&buf, iotest.HalfReader(strings.NewReader(text)))
+	assert.Nil(t, err)
+	assert.Equal(t, buf.String(), "a\xffKEY\xe4\xb8 KEY\xe4")
+	assert.Equal(t, buf.String(), trie.Replace(text))
+}
+
+// sizeWriter records the biggest number of bytes held back by `ReplaceStream`
+type sizeWriter struct {
+	written int
+	read    func() int
+	maxHeld int
+}
+
+func (w *sizeWriter) Write(p []byte) (int, error) {
+	w.written += len(p)
+	if held := w.read() - w.written; held > w.maxHeld {
+		w.maxHeld = held
+	}
+	return len(p), nil
+}
+
+type countReader struct {
+	r    *strings.Reader
+	read int
+}
+
+func (r *countReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.read += n
+	return n, err
+}
+
+func TestReplaceStreamBoundedMemory(t *testing.T) {
+	for _, kind := range allMatchKinds {
+		trie := NewFlashKeywords(true)
+		trie.SetMatchKind(kind)
+		trie.AddKeyWord("needle", "pin")
+		trie.AddKeyWord("needless", "useless")
+		text := strings.Repeat(strings.Repeat("hay ", 20000)+"needle needless ", 20)
+		src := &countReader{r: strings.NewReader(text)}
+		dst := &sizeWriter{read: func() int { return src.read }}
+		n, err := trie.ReplaceStream(dst, src)
+		assert.Nil(t, err)
+		assert.Equal(t, int(n), len(trie.Replace(text)))
+		// the text read but not written is at most one read
+		assert.Equal(t, dst.maxHeld <= streamChunkSize, true, "kind=%v held=%v", kind, dst.maxHeld)
+	}
+}
+
+type errWriter struct{}
+
+func (errWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write error")
+}
+
+func TestReplaceStreamWriteError(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.AddKeyWord("a", "b")
+	n, err := trie.ReplaceStream(errWriter{}, strings.NewReader("a text"))
+	assert.Equal(t, err.Error(), "write error")
+	assert.Equal(t, n, int64(0))
+}
@@ -0,0 +1,120 @@
+package flashtext
+
+import "sync"
+
+// SyncKeywords is a `FlashKeywords` safe for concurrent use: the searches run concurrently
+// and the updates of the trie wait for them, behind a `sync.RWMutex`. A `FlashKeywords` on
+// its own can be searched concurrently but must not be updated while it is searched.
+type SyncKeywords struct {
+	mu   sync.RWMutex
+	tree *FlashKeywords
+}
+
+// Instantiate a new Instance of the `SyncKeywords` with
+// a case sensitive true or false
+func NewSyncKeywords(caseSensitive bool) *SyncKeywords {
+	return &SyncKeywords{tree: NewFlashKeywords(caseSensitive)}
+}
+
+// Update calls `fn` with the trie locked for writing, for the updates and the settings
+// without a method of their own (`SetMatchKind`, `SetBoundaryMode`...). The trie must
+// not be used after `fn` returns
+func (s *SyncKeywords) Update(fn func(tree *FlashKeywords)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s.tree)
+}
+
+// View calls `fn` with the trie locked for reading, concurrently with the other readers.
+// `fn` must not update the trie nor use it after it returns
+func (s *SyncKeywords) View(fn func(tree *FlashKeywords)) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fn(s.tree)
+}
+
+// Add the key `word` into the trie, see `FlashKeywords.Add`
+func (s *SyncKeywords) Add(word string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tree.Add(word)
+}
+
+// Add the key `word` into the trie with the corresponding `cleanWord`,
+// see `FlashKeywords.AddKeyWord`
+func (s *SyncKeywords) AddKeyWord(word string, cleanWord string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tree.AddKeyWord(word, cleanWord)
+}
+
+// Add Multiple Keywords simultaneously from a map, see `FlashKeywords.AddFromMap`
+func (s *SyncKeywords) AddFromMap(keys2synonyms map[string][]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tree.AddFromMap(keys2synonyms)
+}
+
+// Add Multiple Keywords simultaneously from a file, see `FlashKeywords.AddFromFile`.
+// The searches wait for the whole file to be added
+func (s *SyncKeywords) AddFromFile(filePath string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tree.AddFromFile(filePath)
+}
+
+// Remove the key `word` from the trie dictionary, see `FlashKeywords.RemoveKey`
+func (s *SyncKeywords) RemoveKey(word string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tree.RemoveKey(word)
+}
+
+// Returns the number of the keys inside the keys dictionary
+func (s *SyncKeywords) Size() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.Size()
+}
+
+// Check if the key `word` exists in the trie dictionary
+func (s *SyncKeywords) Contains(word string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.Contains(word)
+}
+
+// Returns the corresponding `cleanWord` for the key `word` from the trie
+func (s *SyncKeywords) GetKeysWord(word string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.GetKeysWord(word)
+}
+
+// Returns a map of all the keys in the trie with their `cleanWord`
+func (s *SyncKeywords) GetAllKeywords() map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.GetAllKeywords()
+}
+
+// Search in the text for the stored keys in the trie, see `FlashKeywords.Search`
+func (s *SyncKeywords) Search(text string) []Result {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.Search(text)
+}
+
+// Replace the keys found in the text with their `cleanWord`, see `FlashKeywords.Replace`
+func (s *SyncKeywords) Replace(text string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.Replace(text)
+}
+
+// Same as `Replace` with the options `opts`, see `FlashKeywords.ReplaceWithOptions`
+func (s *SyncKeywords) ReplaceWithOptions(text string, opts ReplaceOptions) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tree.ReplaceWithOptions(text, opts)
+}
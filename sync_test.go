@@ -0,0 +1,89 @@
+package flashtext
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncKeywords(t *testing.T) {
+	s := NewSyncKeywords(false)
+	s.AddKeyWord("java", "lang")
+	s.AddFromMap(map[string][]string{"go": {"golang"}})
+	s.Add("cat")
+	assert.Equal(t, s.Size(), 3)
+	assert.Equal(t, s.Contains("golang"), true)
+	assert.Equal(t, s.RemoveKey("cat"), true)
+	cleanWord, err := s.GetKeysWord("java")
+	assert.Nil(t, err)
+	assert.Equal(t, cleanWord, "lang")
+	assert.Equal(t, s.GetAllKeywords(), map[string]string{"java": "lang", "golang": "go"})
+
+	s.Update(func(tree *FlashKeywords) {
+		tree.SetMatchKind(LeftmostLongest)
+		tree.AddKeyWord("java programing", "skill")
+	})
+	text := "java programing in GOLANG"
+	assert.Equal(t, s.Replace(text), "skill in go")
+	assert.Equal(t, s.ReplaceWithOptions(text, ReplaceOptions{PreserveCase: true}), "skill in GO")
+	assert.Equal(t, len(s.Search(text)), 2)
+	s.View(func(tree *FlashKeywords) {
+		assert.Equal(t, tree.MatchKind(), LeftmostLongest)
+		frozen, err := tree.Freeze()
+		assert.Nil(t, err)
+		assert.Equal(t, frozen.Replace(text), "skill in go")
+	})
+}
+
+func TestSyncKeywordsStress(t *testing.T) {
+	// run with `go test -race`: the readers search while the writers update the trie
+	s := NewSyncKeywords(true)
+	for i := 0; i < 50; i++ {
+		s.AddKeyWord(fmt.Sprintf("key%d", i), "X")
+	}
+	text := strings.Repeat("key1 key12 key123 other key7 ", 20)
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				key := fmt.Sprintf("key%d", (w*200+i)%300)
+				if i%3 == 0 {
+					s.RemoveKey(key)
+				} else if !s.Contains(key) {
+					s.AddKeyWord(key, "X")
+				}
+				if i%50 == 0 {
+					kind := allMatchKinds[(w+i)%len(allMatchKinds)]
+					s.Update(func(tree *FlashKeywords) { tree.SetMatchKind(kind) })
+				}
+			}
+		}(w)
+	}
+	for r := 0; r < 8; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				for _, res := range s.Search(text) {
+					if text[res.Start:res.End] != res.Key {
+						t.Errorf("invalid result %+v", res)
+					}
+				}
+				newText := s.Replace(text)
+				if !strings.Contains(newText, "other") {
+					t.Errorf("invalid replace %q", newText)
+				}
+				s.Contains("key1")
+				s.Size()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, s.Size(), len(s.GetAllKeywords()))
+}
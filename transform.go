@@ -0,0 +1,92 @@
+package flashtext
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/text/transform"
+)
+
+// Returns a `transform.Transformer` replacing the keys found in the text with their
+// `cleanWord` like `Replace`, to be used with `transform.NewReader`, `transform.Chain`...
+// The transformer asks for more input (`transform.ErrShortSrc`) while the end of the
+// source can still be the beginning of a key. It is not safe for concurrent use.
+func (tree *FlashKeywords) Transformer() transform.Transformer {
+	return &replaceTransformer{tree: tree}
+}
+
+// replaceTransformer replaces the keys of the source given by the successive calls to
+// `Transform`. Like `ReplaceStream`, the state of the scanner is kept between the calls and
+// the offsets are absolute from the beginning of the text: the bytes which could be part of
+// a key are left unconsumed and given back at the beginning of the next source.
+type replaceTransformer struct {
+	tree       *FlashKeywords
+	s          scanner
+	started    bool   // `s` is scanning a text, until `Reset`
+	base       int    // absolute offset of src[0], the bytes consumed so far
+	offset     int    // absolute offset of the next rune to give to the scanner
+	lastChange int    // absolute offset of the end of the last replaced key
+	out        []byte // transformed bytes which didn't fit in the previous `dst`
+}
+
+func (t *replaceTransformer) Reset() {
+	t.started = false
+	t.base, t.offset, t.lastChange = 0, 0, 0
+	t.out = t.out[:0]
+}
+
+func (t *replaceTransformer) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	nDst = copy(dst, t.out)
+	t.out = t.out[:copy(t.out, t.out[nDst:])]
+	if len(t.out) > 0 {
+		return nDst, 0, transform.ErrShortDst
+	}
+
+	if !t.started {
+		t.s = t.tree.newScanner()
+		t.started = true
+	}
+	// same as `Replace`, the keys are replaced as soon as they are reported
+	replace := func(m match) {
+		if skipReplace(m, m.node.cleanWord, t.lastChange) {
+			return
+		}
+		t.out = append(t.out, src[t.lastChange-t.base:m.start-t.base]...)
+		t.out = append(t.out, m.node.cleanWord...)
+		t.lastChange = m.end
+	}
+	for t.offset < t.base+len(src) {
+		p := src[t.offset-t.base:]
+		if !atEOF && !utf8.FullRune(p) {
+			// the end of the rune is in the next source
+			break
+		}
+		char, size := utf8.DecodeRune(p)
+		t.s.step(char, t.offset, size, replace)
+		t.offset += size
+	}
+
+	safe := t.offset
+	if atEOF {
+		t.s.finish(t.offset, replace)
+	} else {
+		safe = t.s.safe(t.offset)
+	}
+	// the bytes after `safe` can still be part of a key, they are given back by the next call
+	if safe > t.lastChange {
+		t.out = append(t.out, src[t.lastChange-t.base:safe-t.base]...)
+		t.lastChange = safe
+	}
+	nSrc = t.lastChange - t.base
+	t.base = t.lastChange
+
+	n := copy(dst[nDst:], t.out)
+	nDst += n
+	t.out = t.out[:copy(t.out, t.out[n:])]
+	switch {
+	case len(t.out) > 0:
+		err = transform.ErrShortDst
+	case nSrc < len(src) && !atEOF:
+		err = transform.ErrShortSrc
+	}
+	return nDst, nSrc, err
+}
@@ -0,0 +1,100 @@
+package flashtext
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestTransformerLikeReplace(t *testing.T) {
+	for _, kind := range allMatchKinds {
+		for _, boundary := range []bool{false, true} {
+			trie := newStreamTestTrie(kind, boundary)
+			expected := trie.Replace(streamTestText)
+
+			newText, n, err := transform.String(trie.Transformer(), streamTestText)
+			assert.Nil(t, err)
+			assert.Equal(t, newText, expected, "kind=%v boundary=%v", kind, boundary)
+			assert.Equal(t, n, len(streamTestText))
+
+			// one byte per read: the transformer gets the keys in several pieces
+			r := transform.NewReader(iotest.OneByteReader(strings.NewReader(streamTestText)), trie.Transformer())
+			b, err := io.ReadAll(r)
+			assert.Nil(t, err)
+			assert.Equal(t, string(b), expected, "kind=%v boundary=%v", kind, boundary)
+			t.Logf("kind=%v boundary=%v newText: %v", kind, boundary, newText)
+		}
+
+		// a key without a `cleanWord` overlapping another key: the occurrence of "ba"
+		// overlapping the reported "ab" must not be replaced after a new source
+		trie := NewFlashKeywords(true)
+		trie.SetMatchKind(kind)
+		trie.AddKeyWord("ab", "")
+		trie.AddKeyWord("ba", "X")
+		expected := trie.Replace("abab")
+		r := transform.NewReader(iotest.OneByteReader(strings.NewReader("abab")), trie.Transformer())
+		b, err := io.ReadAll(r)
+		assert.Nil(t, err)
+		assert.Equal(t, string(b), expected, "kind=%v", kind)
+	}
+}
+
+func TestTransformerShortBuffers(t *testing.T) {
+	trie := NewFlashKeywords(false)
+	trie.AddKeyWord("cat", "a very long clean word")
+	trie.AddKeyWord("dog", "")
+	text := "the cat and the dog, CAT"
+	expected := trie.Replace(text)
+
+	tr := trie.Transformer()
+	// the replaced text doesn't fit in `dst` and is given back over several calls
+	dst := make([]byte, 4)
+	var out []byte
+	src := []byte(text)
+	for i := 0; i < 100; i++ {
+		nDst, nSrc, err := tr.Transform(dst, src, true)
+		out = append(out, dst[:nDst]...)
+		src = src[nSrc:]
+		if err == nil {
+			break
+		}
+		assert.Equal(t, err, transform.ErrShortDst)
+	}
+	assert.Equal(t, string(out), expected)
+}
+
+func TestTransformerChain(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.AddKeyWord("café", "coffee")
+	// `e` followed by the combining acute accent, composed into `é` by NFC
+	text := "un café noir"
+	assert.Equal(t, trie.Replace(text), text)
+	newText, _, err := transform.String(transform.Chain(norm.NFC, trie.Transformer()), text)
+	assert.Nil(t, err)
+	assert.Equal(t, newText, "un coffee noir")
+}
+
+func TestTransformerReset(t *testing.T) {
+	trie := NewFlashKeywords(true)
+	trie.SetBoundaryMode(true)
+	trie.AddKeyWord("key", "KEY")
+	tr := trie.Transformer()
+	dst := make([]byte, 64)
+	// the source ends in the middle of a word: `key` can't start after it
+	nDst, nSrc, err := tr.Transform(dst, []byte("a wor"), false)
+	assert.Nil(t, err)
+	assert.Equal(t, string(dst[:nDst]), "a wor")
+	assert.Equal(t, nSrc, 5)
+	nDst, _, _ = tr.Transform(dst, []byte("key"), true)
+	assert.Equal(t, string(dst[:nDst]), "key")
+
+	// a new document starts with a word boundary
+	tr.Reset()
+	nDst, _, _ = tr.Transform(dst, []byte("key"), true)
+	assert.Equal(t, string(dst[:nDst]), "KEY")
+}
@@ -0,0 +1,152 @@
+package flashtext
+
+import (
+	"hash/fnv"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// the options of `Matcher.Watch`:
+//   - `Interval`: the period of the polling of the files, 1s if zero.
+//   - `Debounce`: the files must be left unchanged for this duration before the reload,
+//     so a file being written or several files updated one after the other are reloaded
+//     once. The files are reloaded as soon as a change is seen if zero.
+//   - `OnReload`: called after each reload made by the watcher, from its goroutine.
+type WatchOptions struct {
+	Interval time.Duration
+	Debounce time.Duration
+	OnReload func(event ReloadEvent)
+}
+
+// ReloadEvent is a reload of a `Matcher` made by its watcher:
+//   - `Time`: the end of the reload.
+//   - `Err`: the error of the reload, the previous dictionary is kept if not nil.
+//     The reload is retried at the next pollings, after the `Debounce`, until it succeeds.
+type ReloadEvent struct {
+	Time time.Time
+	Err  error
+}
+
+// Watcher polls the files of a `Matcher` and reloads it when their content changes,
+// see `Matcher.Watch`
+type Watcher struct {
+	matcher *Matcher
+	opts    WatchOptions
+	seen    []fileState // the files as of the last polling
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// fileState identifies the content of a file: the content is hashed again only when
+// the size or the modification time change, and a file touched without change is not reloaded
+type fileState struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+	sum     uint64
+}
+
+// Watch starts a goroutine polling the files of the matcher (modification time, size and
+// hash of the content, without any dependency on the notifications of the system) and
+// reloading the dictionary when they change, until `Stop` is called. The outcome of the
+// reloads is given by `LoadedAt` and `LastError` and to `opts.OnReload`
+func (m *Matcher) Watch(opts WatchOptions) *Watcher {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	w := &Watcher{
+		matcher: m,
+		opts:    opts,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	w.seen = w.poll()
+	go w.run()
+	return w
+}
+
+// Stop stops the polling and waits for the goroutine of the watcher,
+// including a reload in progress
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() { close(w.stop) })
+	<-w.done
+}
+
+func (w *Watcher) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	// time of the last change not reloaded yet or of the last failed reload, zero if none
+	var changedAt time.Time
+	for {
+		select {
+		case <-w.stop:
+			return
+		case now := <-ticker.C:
+			states := w.poll()
+			changed := false
+			for i, state := range states {
+				changed = changed || state.exists != w.seen[i].exists || state.sum != w.seen[i].sum
+			}
+			w.seen = states
+			if changed {
+				changedAt = now
+				if w.opts.Debounce > 0 {
+					continue
+				}
+			}
+			// the reload is retried until it succeeds, also after a failed `Reload` of the caller
+			if (changedAt.IsZero() && w.matcher.LastError() == nil) || now.Sub(changedAt) < w.opts.Debounce {
+				continue
+			}
+			changedAt = time.Time{}
+			err := w.matcher.Reload()
+			if err != nil {
+				changedAt = now
+			}
+			if w.opts.OnReload != nil {
+				w.opts.OnReload(ReloadEvent{Time: time.Now(), Err: err})
+			}
+		}
+	}
+}
+
+// poll returns the state of the files of the matcher
+func (w *Watcher) poll() []fileState {
+	states := make([]fileState, len(w.matcher.paths))
+	for i, path := range w.matcher.paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		state := fileState{exists: true, size: info.Size(), modTime: info.ModTime()}
+		if prev := w.seen; prev != nil && prev[i].exists && prev[i].size == state.size &&
+			prev[i].modTime.Equal(state.modTime) {
+			state.sum = prev[i].sum
+		} else if state.sum, err = hashFile(path); err != nil {
+			// the file is polled again
+			state.exists = false
+		}
+		states[i] = state
+	}
+	return states
+}
+
+func hashFile(path string) (uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	h := fnv.New64a()
+	if _, err := io.Copy(h, f); err != nil {
+		return 0, err
+	}
+	return h.Sum64(), nil
+}
@@ -0,0 +1,117 @@
+package flashtext
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// waitReload returns the next reload event of the watcher
+func waitReload(t *testing.T, events chan ReloadEvent) ReloadEvent {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reload")
+	}
+	return ReloadEvent{}
+}
+
+func TestWatcherReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	assert.Nil(t, os.WriteFile(path, []byte("java=>lang\n"), 0o644))
+	m, err := NewMatcher(newMatcherTestTree, path)
+	assert.Nil(t, err)
+	loadedAt := m.LoadedAt()
+	assert.Equal(t, loadedAt.IsZero(), false)
+
+	events := make(chan ReloadEvent, 10)
+	w := m.Watch(WatchOptions{
+		Interval: 5 * time.Millisecond,
+		Debounce: 20 * time.Millisecond,
+		OnReload: func(event ReloadEvent) { events <- event },
+	})
+	defer w.Stop()
+
+	// several writes in a row are reloaded once
+	assert.Nil(t, os.WriteFile(path, []byte("java=>JVM\n"), 0o644))
+	assert.Nil(t, os.WriteFile(path, []byte("java=>JVM\ngolang=>go\n"), 0o644))
+	event := waitReload(t, events)
+	assert.Nil(t, event.Err)
+	assert.Equal(t, m.Replace("java golang"), "JVM go")
+	assert.Equal(t, m.LoadedAt().After(loadedAt), true)
+	assert.Nil(t, m.LastError())
+
+	// the file removed: the dictionary is kept and the reload is retried without any change
+	assert.Nil(t, os.Remove(path))
+	event = waitReload(t, events)
+	assert.NotNil(t, event.Err)
+	assert.Equal(t, m.LastError(), event.Err)
+	assert.Equal(t, m.Replace("java golang"), "JVM go")
+	event = waitReload(t, events)
+	assert.NotNil(t, event.Err)
+
+	assert.Nil(t, os.WriteFile(path, []byte("java=>lang\n"), 0o644))
+	for event.Err != nil {
+		event = waitReload(t, events)
+	}
+	assert.Nil(t, m.LastError())
+	assert.Equal(t, m.Replace("java golang"), "lang golang")
+	assert.Equal(t, len(events), 0)
+}
+
+func TestWatcherUnchangedContent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	assert.Nil(t, os.WriteFile(path, []byte("java=>lang\n"), 0o644))
+	m, err := NewMatcher(newMatcherTestTree, path)
+	assert.Nil(t, err)
+
+	events := make(chan ReloadEvent, 10)
+	w := m.Watch(WatchOptions{
+		Interval: 5 * time.Millisecond,
+		OnReload: func(event ReloadEvent) { events <- event },
+	})
+	// touched without change
+	later := time.Now().Add(time.Minute)
+	assert.Nil(t, os.Chtimes(path, later, later))
+	time.Sleep(50 * time.Millisecond)
+	w.Stop()
+	w.Stop()
+	assert.Equal(t, len(events), 0)
+
+	// no reload after `Stop`
+	assert.Nil(t, os.WriteFile(path, []byte("java=>JVM\n"), 0o644))
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, len(events), 0)
+	assert.Equal(t, m.Replace("java"), "lang")
+}
+
+func TestWatcherRetry(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "keys.txt")
+	assert.Nil(t, os.WriteFile(path, []byte("java=>lang\n"), 0o644))
+	m, err := NewMatcher(newMatcherTestTree, path)
+	assert.Nil(t, err)
+
+	// the file is unreadable for the reload but its content is unchanged for the watcher
+	assert.Nil(t, os.Rename(path, path+".bak"))
+	assert.Nil(t, os.Mkdir(path, 0o755))
+	assert.NotNil(t, m.Reload())
+	assert.Nil(t, os.Remove(path))
+	assert.Nil(t, os.Rename(path+".bak", path))
+
+	events := make(chan ReloadEvent, 10)
+	w := m.Watch(WatchOptions{
+		Interval: 5 * time.Millisecond,
+		OnReload: func(event ReloadEvent) { events <- event },
+	})
+	defer w.Stop()
+	event := waitReload(t, events)
+	assert.Nil(t, event.Err)
+	assert.Nil(t, m.LastError())
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, len(events), 0)
+}